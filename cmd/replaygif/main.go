@@ -0,0 +1,87 @@
+// Command replaygif renders a replay saved with F8 into an animated GIF,
+// without opening a window or playing sound.
+//
+//	go run ./cmd/replaygif -in replay.txt -out clip.gif -start 12 -end 20
+//
+// -start and -end pick a clip in seconds from the start of the replay.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"go-pacman/game"
+)
+
+func main() {
+	in := flag.String("in", "", "replay file to render")
+	out := flag.String("out", "replay.gif", "GIF file to write")
+	fps := flag.Int("fps", 25, "frames per second (1-50)")
+	scale := flag.Int("scale", 2, "whole-number upscaling factor")
+	start := flag.Float64("start", 0, "start of the clip in seconds")
+	end := flag.Float64("end", 0, "end of the clip in seconds, 0 for the end of the replay")
+	themeID := flag.String("theme", "arcade", "color theme")
+	rulesPath := flag.String("rules", "", "rules file the replay was recorded with")
+	mazeFile := flag.String("maze-file", "", "maze file the replay was recorded with")
+	debug := flag.Bool("debug", false, "draw the debug overlay: ghost targets, tile grid and timers")
+	flag.Parse()
+	if *in == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	replay, err := game.LoadReplay(*in)
+	if err != nil {
+		log.Fatal(err)
+	}
+	theme, err := game.ThemeByID(*themeID)
+	if err != nil {
+		log.Fatal(err)
+	}
+	g := game.New()
+	if err := g.SetTheme(theme); err != nil {
+		log.Fatal(err)
+	}
+	if *rulesPath != "" {
+		rules, err := game.LoadRules(*rulesPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		g.SetRules(rules)
+	}
+	if *mazeFile != "" {
+		if err := g.SetMazeFile(*mazeFile); err != nil {
+			log.Fatal(err)
+		}
+	}
+	g.SetDebugOverlay(*debug)
+	g.StartReplay(replay)
+
+	rec := game.NewGIFRecorder(*fps, *scale)
+	startTick := int(*start * ebiten.DefaultTPS)
+	endTick := int(*end * ebiten.DefaultTPS)
+	for tick := 0; !g.ReplayFinished() && (endTick <= 0 || tick < endTick); tick++ {
+		if tick >= startTick && !rec.Tick(g) {
+			log.Print("clip truncated to the maximum GIF length")
+			break
+		}
+		if err := g.Update(); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	f, err := os.Create(*out)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := rec.Encode(f); err != nil {
+		f.Close()
+		log.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("wrote %s (%d frames)", *out, rec.Frames())
+}
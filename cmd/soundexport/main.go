@@ -0,0 +1,52 @@
+// Command soundexport renders every generated sound effect and jingle to a
+// 16-bit WAV file, for auditioning in an audio editor.
+//
+//	go run ./cmd/soundexport -out sounds
+package main
+
+import (
+	"bufio"
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+
+	"go-pacman/game"
+)
+
+func main() {
+	out := flag.String("out", "sounds", "directory to write the WAV files to")
+	flag.Parse()
+
+	if err := os.MkdirAll(*out, 0o755); err != nil {
+		log.Fatal(err)
+	}
+	for _, name := range game.SoundNames() {
+		pcm, err := game.RenderSound(name)
+		if err != nil {
+			log.Fatal(err)
+		}
+		path := filepath.Join(*out, name+".wav")
+		if err := writeWAV(path, pcm); err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("wrote %s", path)
+	}
+}
+
+func writeWAV(path string, pcm []byte) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	if err := game.WriteWAV(w, pcm, game.SampleRate); err != nil {
+		f.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
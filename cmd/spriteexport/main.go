@@ -0,0 +1,50 @@
+// Command spriteexport writes every generated sprite to a PNG sprite sheet.
+//
+//	go run ./cmd/spriteexport -out sprites.png -theme mspacman
+//
+// By default the sheet is labelled for reviewing the artwork. With -labels=false
+// it uses the layout -sprites reads, as a starting point for a custom sheet.
+package main
+
+import (
+	"flag"
+	"image"
+	"image/png"
+	"log"
+	"os"
+
+	"go-pacman/game"
+)
+
+func writePNG(path string, img image.Image) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func main() {
+	out := flag.String("out", "sprites.png", "PNG file to write")
+	themeID := flag.String("theme", "arcade", "color theme to render the sprites in")
+	labelled := flag.Bool("labels", true, "label each sprite and include the wall pieces")
+	flag.Parse()
+
+	theme, err := game.ThemeByID(*themeID)
+	if err != nil {
+		log.Fatal(err)
+	}
+	g := game.New()
+	if err := g.SetTheme(theme); err != nil {
+		log.Fatal(err)
+	}
+
+	if err := writePNG(*out, game.SpriteSheetImage(*labelled)); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("wrote %s", *out)
+}
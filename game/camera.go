@@ -0,0 +1,194 @@
+package game
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// MaxViewCols and MaxViewRows are the most of a maze the screen shows at
+// once. The camera scrolls across larger mazes, following Pac-Man.
+const (
+	MaxViewCols = 40
+	MaxViewRows = 35
+)
+
+// cameraSmoothing is the fraction of the way to its target the camera moves
+// each tick.
+const cameraSmoothing = 0.15
+
+// camera is the part of the maze on screen, as the maze pixel shown at the
+// top left corner of the view.
+type camera struct {
+	X, Y float64
+}
+
+// viewSize returns the size of the view onto the maze: the whole maze, or
+// MaxViewCols x MaxViewRows tiles of a larger one.
+func (g *Game) viewSize() (w, h int) {
+	return min(g.maze.Width, MaxViewCols) * TileSize, min(g.maze.Height, MaxViewRows) * TileSize
+}
+
+// viewRect returns where the view onto the maze is on the game screen. The
+// view, its HUD rows and the minimap beside it are centered on screens larger
+// than they need.
+func (g *Game) viewRect() image.Rectangle {
+	w, h := g.screenSize()
+	vw, vh := g.viewSize()
+	x := (w - vw - g.minimapWidth()) / 2
+	y := (h-vh-(HUDTopRows+HUDBotRows)*TileSize)/2 + HUDTopRows*TileSize
+	return image.Rect(x, y, x+vw, y+vh)
+}
+
+// viewGeoM returns the view transform, from maze pixels to the game screen.
+// The camera is rounded to whole pixels so tiles and sprites stay sharp.
+func (g *Game) viewGeoM() ebiten.GeoM {
+	r := g.viewRect()
+	var geo ebiten.GeoM
+	geo.Translate(float64(r.Min.X)-math.Round(g.camera.X), float64(r.Min.Y)-math.Round(g.camera.Y))
+	return geo
+}
+
+// toScreen returns where maze pixel x, y is on the game screen.
+func (g *Game) toScreen(x, y float64) (float64, float64) {
+	geo := g.viewGeoM()
+	return geo.Apply(x, y)
+}
+
+// toMaze returns the maze pixel at x, y on the game screen.
+func (g *Game) toMaze(x, y float64) (float64, float64) {
+	geo := g.viewGeoM()
+	geo.Invert()
+	return geo.Apply(x, y)
+}
+
+// cameraTarget returns where the camera heads: Pac-Man in the middle of the
+// view, kept inside the maze.
+func (g *Game) cameraTarget() (x, y float64) {
+	vw, vh := g.viewSize()
+	return g.clampCamera(g.pacman.X-float64(vw)/2, g.pacman.Y-float64(vh)/2)
+}
+
+// clampCamera keeps a camera position inside the maze.
+func (g *Game) clampCamera(x, y float64) (float64, float64) {
+	vw, vh := g.viewSize()
+	maxX, maxY := float64(g.maze.Width*TileSize-vw), float64(g.maze.Height*TileSize-vh)
+	return min(max(x, 0), maxX), min(max(y, 0), maxY)
+}
+
+// updateCamera moves the camera part of the way to its target. It jumps
+// when Pac-Man does, through a tunnel, rather than panning across the maze.
+func (g *Game) updateCamera() {
+	x, y := g.cameraTarget()
+	vw, vh := g.viewSize()
+	if math.Abs(x-g.camera.X) > float64(vw)/2 || math.Abs(y-g.camera.Y) > float64(vh)/2 {
+		g.snapCamera()
+		return
+	}
+	g.camera.X += (x - g.camera.X) * cameraSmoothing
+	g.camera.Y += (y - g.camera.Y) * cameraSmoothing
+}
+
+// snapCamera puts the camera straight on its target.
+func (g *Game) snapCamera() {
+	g.camera.X, g.camera.Y = g.cameraTarget()
+}
+
+// panCamera moves the camera by dx, dy maze pixels, inside the maze.
+func (g *Game) panCamera(dx, dy float64) {
+	g.camera.X, g.camera.Y = g.clampCamera(g.camera.X+dx, g.camera.Y+dy)
+}
+
+// maskView clears the screen around the view, hiding the parts of a large
+// maze and of tunnel sprites that are drawn outside it.
+func (g *Game) maskView(screen Canvas) {
+	w, h := g.screenSize()
+	r := g.viewRect()
+	bg := theme.Palette.Background
+	fillRect(screen, 0, 0, float64(w), float64(r.Min.Y), bg)
+	fillRect(screen, 0, float64(r.Max.Y), float64(w), float64(h-r.Max.Y), bg)
+	fillRect(screen, 0, float64(r.Min.Y), float64(r.Min.X), float64(r.Dy()), bg)
+	fillRect(screen, float64(r.Max.X), float64(r.Min.Y), float64(w-r.Max.X), float64(r.Dy()), bg)
+}
+
+// Minimap colors for its background and the view's outline.
+var (
+	minimapPanel = color.RGBA{0x00, 0x00, 0x00, 0xC0}
+	minimapView  = color.RGBA{0x80, 0x80, 0x80, 0xFF}
+)
+
+// minimapMargin is the gap in pixels between the view and the minimap.
+const minimapMargin = 4
+
+// minimapScale returns the minimap's pixels per tile: as many as fit the
+// view's height and a quarter of its width, at most two.
+func (g *Game) minimapScale() float64 {
+	vw, vh := g.viewSize()
+	return min(2, float64(vw)/4/float64(g.maze.Width), float64(vh)/float64(g.maze.Height))
+}
+
+// minimapWidth returns the width of the minimap's column to the right of the
+// view, or 0 when the minimap is turned off.
+func (g *Game) minimapWidth() int {
+	if !g.settings.Minimap {
+		return 0
+	}
+	return int(math.Ceil(float64(g.maze.Width)*g.minimapScale())) + 2*minimapMargin
+}
+
+// renderMinimap draws m's walls a pixel per tile, for drawMinimap to scale
+// down beside the view.
+func renderMinimap(m *Maze) *ebiten.Image {
+	img := newRGBA(m.Width, m.Height)
+	for y := 0; y < m.Height; y++ {
+		for x := 0; x < m.Width; x++ {
+			if m.TileAt(x, y) == TileWall {
+				img.SetRGBA(x, y, theme.Palette.Wall)
+			}
+		}
+	}
+	return newImage(img)
+}
+
+// drawMinimap draws the whole maze scaled down beside the top right corner
+// of the view, with Pac-Man, the ghosts in their colors and the part of the
+// maze on screen.
+func (g *Game) drawMinimap(screen Canvas) {
+	s := g.minimapScale()
+	r := g.viewRect()
+	w, h := float64(g.maze.Width)*s, float64(g.maze.Height)*s
+	left, top := float64(r.Max.X+minimapMargin), float64(r.Min.Y)
+	fillRect(screen, left-1, top-1, w+2, h+2, minimapPanel)
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(s, s)
+	op.GeoM.Translate(left, top)
+	screen.DrawImage(g.minimap, op)
+
+	// The view's outline, when the maze does not fit it
+	vw, vh := g.viewSize()
+	if vw < g.maze.Width*TileSize || vh < g.maze.Height*TileSize {
+		vx, vy := left+g.camera.X/TileSize*s, top+g.camera.Y/TileSize*s
+		ow, oh := float64(vw)/TileSize*s, float64(vh)/TileSize*s
+		fillRect(screen, vx, vy, ow, 1, minimapView)
+		fillRect(screen, vx, vy+oh-1, ow, 1, minimapView)
+		fillRect(screen, vx, vy, 1, oh, minimapView)
+		fillRect(screen, vx+ow-1, vy, 1, oh, minimapView)
+	}
+
+	dot := func(x, y float64, c color.Color) {
+		fillRect(screen, left+x/TileSize*s-1, top+y/TileSize*s-1, 2, 2, c)
+	}
+	for _, ghost := range g.ghosts {
+		c := theme.Palette.Ghosts[ghost.ID]
+		switch ghost.Mode {
+		case GhostFrightened:
+			c = theme.Palette.Frightened
+		case GhostEaten:
+			c = theme.Palette.Eyes
+		}
+		dot(ghost.X, ghost.Y, c)
+	}
+	dot(g.pacman.X, g.pacman.Y, theme.Palette.PacMan)
+}
@@ -0,0 +1,121 @@
+package game
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// openLayout returns a w x h board of dots inside a wall.
+func openLayout(w, h int) []string {
+	layout := make([]string, h)
+	for y := range layout {
+		if y == 0 || y == h-1 {
+			layout[y] = strings.Repeat("#", w)
+		} else {
+			layout[y] = "#" + strings.Repeat(".", w-2) + "#"
+		}
+	}
+	return layout
+}
+
+func TestCameraFollowsPacMan(t *testing.T) {
+	g := New()
+	g.setMaze(NewMazeFromLayout(openLayout(100, 80)))
+	g.spawnActors()
+	// Pac-Man starts at 116, 188: centered in the 320x280 view, but no
+	// further left than the maze's edge
+	if g.camera != (camera{0, 48}) {
+		t.Fatalf("got the camera at %v after spawning, want {0 48}", g.camera)
+	}
+
+	g.pacman.X, g.pacman.Y = 200, 300
+	g.updateCamera()
+	if want := (camera{6, 64.8}); math.Abs(g.camera.X-want.X) > 1e-9 || math.Abs(g.camera.Y-want.Y) > 1e-9 {
+		t.Errorf("got the camera at %v after one tick, want it %v of the way to {40 160}", g.camera, cameraSmoothing)
+	}
+	for range 200 {
+		g.updateCamera()
+	}
+	if math.Abs(g.camera.X-40) > 0.5 || math.Abs(g.camera.Y-160) > 0.5 {
+		t.Errorf("got the camera at %v, want it settled at {40 160}", g.camera)
+	}
+
+	// Going through a tunnel jumps the camera rather than panning across
+	g.pacman.X, g.pacman.Y = 796, 636
+	g.updateCamera()
+	if g.camera != (camera{480, 360}) {
+		t.Errorf("got the camera at %v, want it jumped and clamped to {480 360}", g.camera)
+	}
+	if x, y := g.toScreen(796, 636); x != 316 || y != 300 {
+		t.Errorf("got Pac-Man on screen at %v, %v, want 316, 300", x, y)
+	}
+	if x, y := g.toMaze(316, 300); x != 796 || y != 636 {
+		t.Errorf("got screen pixel 316, 300 at maze pixel %v, %v, want 796, 636", x, y)
+	}
+}
+
+func TestCameraStillOnFittingMaze(t *testing.T) {
+	g := New()
+	g.spawnActors()
+	for _, pos := range [][2]float64{{4, 4}, {220, 244}, {116, 188}} {
+		g.pacman.X, g.pacman.Y = pos[0], pos[1]
+		g.updateCamera()
+		if g.camera != (camera{}) {
+			t.Errorf("got the camera at %v with Pac-Man at %v, want it still on the classic maze", g.camera, pos)
+		}
+	}
+}
+
+func TestMinimapBesideView(t *testing.T) {
+	tests := []struct {
+		name          string
+		maze          *Maze
+		width, height int
+		view          image.Rectangle
+	}{
+		{"classic", NewMaze(), 288, 288, image.Rect(0, 24, 224, 272)},
+		{"huge", NewMazeFromLayout(openLayout(100, 80)), 408, 320, image.Rect(0, 24, 320, 304)},
+	}
+	for _, tt := range tests {
+		g := New()
+		g.settings.Minimap = true
+		g.setMaze(tt.maze)
+		if w, h := g.screenSize(); w != tt.width || h != tt.height {
+			t.Errorf("%s: got a %dx%d screen, want %dx%d", tt.name, w, h, tt.width, tt.height)
+		}
+		if got := g.viewRect(); got != tt.view {
+			t.Errorf("%s: got the view at %v, want %v", tt.name, got, tt.view)
+		}
+		// The minimap's panel, with its border, is right of the view
+		if left := g.viewRect().Max.X + minimapMargin - 1; left <= tt.view.Max.X {
+			t.Errorf("%s: the minimap at x %d overlaps the view", tt.name, left)
+		}
+	}
+}
+
+func TestMinimapFollowsMaze(t *testing.T) {
+	g := New()
+	g.settings.Minimap = true
+	g.state = StatePlaying
+	// The classic maze's minimap is at 2 pixels per tile, from 228,24
+	at := func(x, y int) color.RGBA {
+		return g.Frame().RGBAAt(228+2*x, 24+2*y)
+	}
+	if got := at(0, 0); got != theme.Palette.Wall {
+		t.Errorf("the corner wall: got %v, want %v", got, theme.Palette.Wall)
+	}
+	if got := at(1, 1); got == theme.Palette.Wall {
+		t.Error("the first dot's tile should not be a wall")
+	}
+
+	layout := slices.Clone(mazeLayout)
+	layout[1] = "##...........##............#"
+	g.setMaze(NewMazeFromLayout(layout))
+	if got := at(1, 1); got != theme.Palette.Wall {
+		t.Errorf("a new maze's wall: got %v, want %v", got, theme.Palette.Wall)
+	}
+}
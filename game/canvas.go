@@ -0,0 +1,122 @@
+package game
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Canvas is a surface the game draws frames on. *ebiten.Image is one;
+// SoftCanvas draws into memory, for screenshots, recordings and rendering
+// without a window.
+type Canvas interface {
+	DrawImage(img *ebiten.Image, op *ebiten.DrawImageOptions)
+	Fill(c color.Color)
+}
+
+// imagePixels keeps a CPU copy of every image the game generates, so a
+// SoftCanvas can draw them. Ebitengine can only read pixels back from the GPU
+// while its game loop runs.
+var imagePixels = map[*ebiten.Image]*image.RGBA{}
+
+// newRGBA returns a transparent w x h image to generate a sprite in.
+func newRGBA(w, h int) *image.RGBA {
+	return image.NewRGBA(image.Rect(0, 0, w, h))
+}
+
+// fillRGBA fills img with c.
+func fillRGBA(img *image.RGBA, c color.Color) {
+	draw.Draw(img, img.Bounds(), image.NewUniform(c), image.Point{}, draw.Src)
+}
+
+// newImage uploads img as an Ebitengine image and keeps img as its CPU copy.
+// img must not be changed afterwards.
+func newImage(img *image.RGBA) *ebiten.Image {
+	e := ebiten.NewImageFromImage(img)
+	imagePixels[e] = img
+	return e
+}
+
+// forgetImage drops the CPU copy of an image that is no longer drawn.
+func forgetImage(img *ebiten.Image) {
+	delete(imagePixels, img)
+}
+
+// SoftCanvas is a Canvas backed by an in-memory image. It draws the images
+// created by the game with nearest-neighbour sampling, like Ebitengine's
+// default filter, honoring the options' GeoM and ColorScale.
+type SoftCanvas struct {
+	Image *image.RGBA
+}
+
+// NewSoftCanvas returns a transparent w x h canvas.
+func NewSoftCanvas(w, h int) *SoftCanvas {
+	return &SoftCanvas{Image: newRGBA(w, h)}
+}
+
+// Fill sets every pixel to c.
+func (c *SoftCanvas) Fill(clr color.Color) {
+	fillRGBA(c.Image, clr)
+}
+
+// DrawImage draws img with source-over blending. It panics for images that
+// were not created by the game, as those have no CPU copy.
+func (c *SoftCanvas) DrawImage(img *ebiten.Image, op *ebiten.DrawImageOptions) {
+	src, ok := imagePixels[img]
+	if !ok {
+		panic("game: SoftCanvas cannot draw an image without a CPU copy")
+	}
+	if op == nil {
+		op = &ebiten.DrawImageOptions{}
+	}
+	geo := op.GeoM
+	if !geo.IsInvertible() {
+		return
+	}
+
+	// Bounding box of the transformed source in destination space
+	sb := src.Bounds()
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, p := range [4]image.Point{sb.Min, {sb.Max.X, sb.Min.Y}, {sb.Min.X, sb.Max.Y}, sb.Max} {
+		x, y := geo.Apply(float64(p.X), float64(p.Y))
+		minX, minY = math.Min(minX, x), math.Min(minY, y)
+		maxX, maxY = math.Max(maxX, x), math.Max(maxY, y)
+	}
+	r := image.Rect(int(math.Floor(minX)), int(math.Floor(minY)), int(math.Ceil(maxX)), int(math.Ceil(maxY)))
+	r = r.Intersect(c.Image.Bounds())
+
+	inv := geo
+	inv.Invert()
+	cs := op.ColorScale
+	scale := [4]float64{float64(cs.R()), float64(cs.G()), float64(cs.B()), float64(cs.A())}
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			// Sample the source at the destination pixel's center
+			u, v := inv.Apply(float64(x)+0.5, float64(y)+0.5)
+			sp := image.Pt(int(math.Floor(u)), int(math.Floor(v)))
+			if !sp.In(sb) {
+				continue
+			}
+			s := src.RGBAAt(sp.X, sp.Y)
+			if s.A == 0 {
+				continue
+			}
+			d := c.Image.RGBAAt(x, y)
+			a := float64(s.A) * scale[3]
+			k := 1 - a/0xFF
+			blend := func(sv, dv uint8, sc float64) uint8 {
+				return uint8(math.Round(math.Min(float64(sv)*sc+float64(dv)*k, 0xFF)))
+			}
+			c.Image.SetRGBA(x, y, color.RGBA{
+				R: blend(s.R, d.R, scale[0]),
+				G: blend(s.G, d.G, scale[1]),
+				B: blend(s.B, d.B, scale[2]),
+				A: blend(s.A, d.A, scale[3]),
+			})
+		}
+	}
+}
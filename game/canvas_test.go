@@ -0,0 +1,84 @@
+package game
+
+import (
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+func TestSoftCanvasDrawImage(t *testing.T) {
+	// A 2x1 source: red then green
+	src := newRGBA(2, 1)
+	red := color.RGBA{R: 0xFF, A: 0xFF}
+	green := color.RGBA{G: 0xFF, A: 0xFF}
+	src.SetRGBA(0, 0, red)
+	src.SetRGBA(1, 0, green)
+	img := newImage(src)
+	defer forgetImage(img)
+
+	black := color.RGBA{A: 0xFF}
+	tests := []struct {
+		name  string
+		geo   func(*ebiten.GeoM)
+		pixel map[[2]int]color.RGBA
+	}{
+		{"translate", func(m *ebiten.GeoM) { m.Translate(1, 2) },
+			map[[2]int]color.RGBA{{1, 2}: red, {2, 2}: green, {0, 2}: black, {1, 1}: black}},
+		{"flip", func(m *ebiten.GeoM) { m.Scale(-1, 1); m.Translate(2, 0) },
+			map[[2]int]color.RGBA{{0, 0}: green, {1, 0}: red}},
+		{"rotate", func(m *ebiten.GeoM) { m.Rotate(math.Pi / 2); m.Translate(1, 0) },
+			map[[2]int]color.RGBA{{0, 0}: red, {0, 1}: green, {1, 0}: black}},
+		{"scale", func(m *ebiten.GeoM) { m.Scale(2, 2) },
+			map[[2]int]color.RGBA{{0, 0}: red, {1, 1}: red, {2, 0}: green, {3, 1}: green}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewSoftCanvas(4, 4)
+			c.Fill(black)
+			op := &ebiten.DrawImageOptions{}
+			tt.geo(&op.GeoM)
+			c.DrawImage(img, op)
+			for p, want := range tt.pixel {
+				if got := c.Image.RGBAAt(p[0], p[1]); got != want {
+					t.Errorf("pixel %v: got %v, want %v", p, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSoftCanvasColorScale(t *testing.T) {
+	src := newRGBA(1, 1)
+	src.SetRGBA(0, 0, color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF})
+	img := newImage(src)
+	defer forgetImage(img)
+
+	c := NewSoftCanvas(1, 1)
+	c.Fill(color.RGBA{B: 0xFF, A: 0xFF})
+	op := &ebiten.DrawImageOptions{}
+	// Half-transparent yellow over blue
+	op.ColorScale.ScaleWithColor(color.RGBA{R: 0x80, G: 0x80, A: 0x80})
+	c.DrawImage(img, op)
+	want := color.RGBA{R: 0x80, G: 0x80, B: 0x7F, A: 0xFF}
+	if got := c.Image.RGBAAt(0, 0); got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestFrameDrawsMaze(t *testing.T) {
+	g := New()
+	g.state = StatePlaying
+	frame := g.Frame()
+	maze := imagePixels[g.mazeImage]
+	// The top wall of the maze, with nothing drawn over it
+	for y := 0; y < TileSize; y++ {
+		for x := 0; x < MinScreenWidth; x++ {
+			want := maze.RGBAAt(x, y)
+			if got := frame.RGBAAt(x, y+HUDTopRows*TileSize); got != want {
+				t.Fatalf("pixel %d,%d: got %v, want %v", x, y, got, want)
+			}
+		}
+	}
+}
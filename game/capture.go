@@ -0,0 +1,240 @@
+package game
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"io"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// GIF recording limits. GIF delays are in hundredths of a second and many
+// viewers slow down anything shorter than two, so 50 fps is the fastest
+// useful rate.
+const (
+	defaultGIFFPS = 25
+	maxGIFFPS     = 50
+	maxGIFSeconds = 120
+)
+
+// SetCaptureDir sets where F12 screenshots, F9 recordings and F8 replays are
+// saved. The default is the working directory.
+func (g *Game) SetCaptureDir(dir string) {
+	g.captureDir = dir
+}
+
+// SetGIFFPS sets the frame rate of F9 recordings, clamped to 1-50.
+func (g *Game) SetGIFFPS(fps int) {
+	g.gifFPS = max(1, min(fps, maxGIFFPS))
+}
+
+// Frame renders the current frame into memory.
+func (g *Game) Frame() *image.RGBA {
+	c := NewSoftCanvas(g.screenSize())
+	g.DrawTo(c)
+	return c.Image
+}
+
+// updateCapture handles the capture keys and records a frame when one is due.
+func (g *Game) updateCapture() {
+	if inpututil.IsKeyJustPressed(ebiten.KeyF12) {
+		g.saveScreenshot()
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyF8) {
+		g.saveReplay()
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyF9) {
+		if g.recorder == nil {
+			g.recorder = NewGIFRecorder(g.gifFPS, 1)
+			log.Printf("recording at %d fps, F9 to stop", g.gifFPS)
+		} else {
+			g.stopRecording()
+		}
+	}
+	if g.recorder != nil && !g.recorder.Tick(g) {
+		g.stopRecording()
+	}
+}
+
+// capturePath returns a new file name in the capture directory.
+func (g *Game) capturePath(kind, ext string) string {
+	name := fmt.Sprintf("go-pacman-%s-%s%s", kind, time.Now().Format("20060102-150405.000"), ext)
+	return filepath.Join(g.captureDir, name)
+}
+
+func (g *Game) saveScreenshot() {
+	path := g.capturePath("screenshot", ".png")
+	if err := writeCapture(path, func(w io.Writer) error {
+		return png.Encode(w, g.Frame())
+	}); err != nil {
+		log.Printf("screenshot: %v", err)
+		return
+	}
+	log.Printf("saved %s", path)
+}
+
+// saveReplay saves the replay of the current or most recent game.
+func (g *Game) saveReplay() {
+	if g.replay == nil {
+		log.Print("replay: no game played yet")
+		return
+	}
+	path := g.capturePath("replay", ".txt")
+	if err := SaveReplay(path, g.replay); err != nil {
+		log.Print(err)
+		return
+	}
+	log.Printf("saved %s", path)
+}
+
+// stopRecording ends the recording and encodes it in the background, so the
+// game does not stall.
+func (g *Game) stopRecording() {
+	rec, path := g.recorder, g.capturePath("recording", ".gif")
+	g.recorder = nil
+	go func() {
+		if err := writeCapture(path, rec.Encode); err != nil {
+			log.Printf("recording: %v", err)
+			return
+		}
+		log.Printf("saved %s (%d frames)", path, rec.Frames())
+	}()
+}
+
+// writeCapture creates path, creating the capture directory if needed, and
+// writes it with write.
+func writeCapture(path string, write func(io.Writer) error) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// GIFRecorder collects game frames at a fixed rate into an animated GIF.
+type GIFRecorder struct {
+	fps   int
+	scale int
+	ticks int
+	anim  gif.GIF
+}
+
+// NewGIFRecorder returns a recorder capturing fps frames per second of play,
+// each scaled up by a whole factor. fps is clamped to 1-50.
+func NewGIFRecorder(fps, scale int) *GIFRecorder {
+	return &GIFRecorder{fps: max(1, min(fps, maxGIFFPS)), scale: max(1, scale)}
+}
+
+// Tick advances the recording by one game tick and captures g's current
+// frame when the next GIF frame is due. It reports false once the recording
+// has reached its maximum length.
+func (r *GIFRecorder) Tick(g *Game) bool {
+	if len(r.anim.Image) >= r.fps*maxGIFSeconds {
+		return false
+	}
+	if r.ticks*r.fps >= len(r.anim.Image)*ebiten.DefaultTPS {
+		r.AddFrame(g.Frame())
+	}
+	r.ticks++
+	return true
+}
+
+// AddFrame appends a frame. Delays are rounded so that the total stays in step
+// with the frame rate, e.g. 3, 4, 3 hundredths of a second at 30 fps.
+func (r *GIFRecorder) AddFrame(img *image.RGBA) {
+	n := len(r.anim.Image)
+	at := func(i int) int {
+		return int(math.Round(float64(i) * 100 / float64(r.fps)))
+	}
+	r.anim.Image = append(r.anim.Image, paletted(img, r.scale))
+	r.anim.Delay = append(r.anim.Delay, at(n+1)-at(n))
+}
+
+// Frames returns the number of frames captured.
+func (r *GIFRecorder) Frames() int {
+	return len(r.anim.Image)
+}
+
+// Encode writes the recording as a looping GIF.
+func (r *GIFRecorder) Encode(w io.Writer) error {
+	if len(r.anim.Image) == 0 {
+		return fmt.Errorf("no frames recorded")
+	}
+	// Loading a maze of another size changes the frame size, so the GIF is
+	// as large as the largest frame
+	for _, img := range r.anim.Image {
+		r.anim.Config.Width = max(r.anim.Config.Width, img.Rect.Dx())
+		r.anim.Config.Height = max(r.anim.Config.Height, img.Rect.Dy())
+	}
+	return gif.EncodeAll(w, &r.anim)
+}
+
+// paletted converts a frame for GIF encoding, scaled up by scale. Frames with
+// 256 colors or fewer, which is all of the game's themes, keep their exact
+// colors; others are mapped to the nearest Plan 9 palette color.
+func paletted(img *image.RGBA, scale int) *image.Paletted {
+	b := img.Bounds()
+	pal := color.Palette{}
+	index := map[color.RGBA]uint8{}
+	for y := b.Min.Y; y < b.Max.Y && pal != nil; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := img.RGBAAt(x, y)
+			if _, ok := index[c]; ok {
+				continue
+			}
+			if len(pal) == 256 {
+				pal = nil
+				break
+			}
+			index[c] = uint8(len(pal))
+			pal = append(pal, c)
+		}
+	}
+
+	out := image.NewPaletted(image.Rect(0, 0, b.Dx()*scale, b.Dy()*scale), pal)
+	if pal == nil {
+		out.Palette = palette.Plan9
+		src := img
+		if scale > 1 {
+			src = scaleRGBA(img, scale)
+		}
+		draw.Draw(out, out.Bounds(), src, src.Bounds().Min, draw.Src)
+		return out
+	}
+	for y := range out.Rect.Dy() {
+		for x := range out.Rect.Dx() {
+			out.SetColorIndex(x, y, index[img.RGBAAt(b.Min.X+x/scale, b.Min.Y+y/scale)])
+		}
+	}
+	return out
+}
+
+// scaleRGBA scales img up by a whole factor with nearest-neighbor sampling.
+func scaleRGBA(img *image.RGBA, scale int) *image.RGBA {
+	b := img.Bounds()
+	out := newRGBA(b.Dx()*scale, b.Dy()*scale)
+	for y := range out.Rect.Dy() {
+		for x := range out.Rect.Dx() {
+			out.SetRGBA(x, y, img.RGBAAt(b.Min.X+x/scale, b.Min.Y+y/scale))
+		}
+	}
+	return out
+}
@@ -0,0 +1,92 @@
+package game
+
+import (
+	"bytes"
+	"image/color"
+	"image/gif"
+	"testing"
+)
+
+func TestGIFRecorderFrameRate(t *testing.T) {
+	tests := []struct {
+		fps        int
+		frames     int   // captured in one second of play
+		firstDelay []int // hundredths of a second
+	}{
+		{10, 10, []int{10, 10, 10}},
+		{25, 25, []int{4, 4, 4}},
+		{30, 30, []int{3, 4, 3, 3, 4, 3}},
+		{60, 50, []int{2, 2, 2}}, // clamped to 50 fps
+	}
+	for _, tt := range tests {
+		g := New()
+		rec := NewGIFRecorder(tt.fps, 1)
+		for range 60 {
+			rec.Tick(g)
+		}
+		if rec.Frames() != tt.frames {
+			t.Errorf("%d fps: got %d frames, want %d", tt.fps, rec.Frames(), tt.frames)
+			continue
+		}
+		total := 0
+		for i, d := range rec.anim.Delay {
+			if i < len(tt.firstDelay) && d != tt.firstDelay[i] {
+				t.Errorf("%d fps: delay %d is %d, want %d", tt.fps, i, d, tt.firstDelay[i])
+			}
+			total += d
+		}
+		if total != 100 {
+			t.Errorf("%d fps: one second of frames lasts %d hundredths", tt.fps, total)
+		}
+	}
+}
+
+func TestGIFRecorderEncode(t *testing.T) {
+	g := New()
+	g.state = StatePlaying
+	rec := NewGIFRecorder(20, 2)
+	for range 6 {
+		rec.Tick(g)
+	}
+	var buf bytes.Buffer
+	if err := rec.Encode(&buf); err != nil {
+		t.Fatal(err)
+	}
+	anim, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(anim.Image) != 2 {
+		t.Fatalf("frames: got %d, want 2", len(anim.Image))
+	}
+	img := anim.Image[0]
+	if w, h := img.Bounds().Dx(), img.Bounds().Dy(); w != 2*MinScreenWidth || h != 2*MinScreenHeight {
+		t.Errorf("size: got %dx%d, want %dx%d", w, h, 2*MinScreenWidth, 2*MinScreenHeight)
+	}
+
+	// The theme has few colors, so they are kept exactly
+	frame := g.Frame()
+	for _, p := range [][2]int{{0, 0}, {10, 30}, {111, 200}} {
+		want := frame.RGBAAt(p[0], p[1])
+		r, gr, b, a := img.At(2*p[0]+1, 2*p[1]+1).RGBA()
+		got := color.RGBA{uint8(r >> 8), uint8(gr >> 8), uint8(b >> 8), uint8(a >> 8)}
+		if got != want {
+			t.Errorf("pixel %v: got %v, want %v", p, got, want)
+		}
+	}
+}
+
+func TestPalettedFallsBackToPlan9(t *testing.T) {
+	img := newRGBA(32, 32)
+	for i := range 32 * 32 {
+		img.SetRGBA(i%32, i/32, color.RGBA{R: uint8(i), G: uint8(i >> 2), B: 0x40, A: 0xFF})
+	}
+	if got := len(paletted(img, 1).Palette); got != 256 {
+		t.Errorf("1024 colors: got a palette of %d, want Plan 9's 256", got)
+	}
+	small := newRGBA(4, 4)
+	fillRGBA(small, color.RGBA{R: 0xFF, A: 0xFF})
+	if got := len(paletted(small, 3).Palette); got != 1 {
+		t.Errorf("one color: got a palette of %d, want 1", got)
+	}
+}
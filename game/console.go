@@ -0,0 +1,294 @@
+//go:build debug
+
+package game
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// Console layout: the last few output lines above a prompt, at the bottom of
+// the screen.
+const (
+	consoleLines      = 8
+	consoleLineHeight = fontHeight + 2
+)
+
+var consolePanel = color.RGBA{0x00, 0x00, 0x00, 0xD8}
+
+// debugConsole is a developer console for cheats and stepping through the
+// simulation. Backtick opens and closes it; the game does not update while it
+// is open. Build with -tags debug to include it.
+type debugConsole struct {
+	open   bool
+	input  string
+	last   string   // last command run, repeated by Enter on an empty line
+	output []string // most recent last
+
+	paused  bool
+	steps   int // ticks to run while paused
+	godMode bool
+}
+
+// updateConsole handles the console keys. It reports true when the game
+// should not update this tick: while the console is open or the game is
+// paused, unless a step is due.
+func (g *Game) updateConsole() bool {
+	c := &g.console
+	if inpututil.IsKeyJustPressed(ebiten.KeyBackquote) {
+		c.open = !c.open
+		return true
+	}
+	if c.open {
+		for _, r := range ebiten.AppendInputChars(nil) {
+			if r != '`' {
+				c.input += string(r)
+			}
+		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyBackspace) && c.input != "" {
+			c.input = c.input[:len(c.input)-1]
+		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeyNumpadEnter) {
+			line := strings.TrimSpace(c.input)
+			if line == "" {
+				line = c.last
+			}
+			c.input = ""
+			if line != "" {
+				c.last = line
+				c.print("> " + line)
+				c.print(g.runCommand(line))
+			}
+		}
+	}
+	if c.steps > 0 {
+		c.steps--
+		return false
+	}
+	return c.open || c.paused
+}
+
+func (c *debugConsole) print(text string) {
+	if text == "" {
+		return
+	}
+	c.output = append(c.output, strings.Split(text, "\n")...)
+	if n := len(c.output); n > consoleLines {
+		c.output = c.output[n-consoleLines:]
+	}
+}
+
+func (g *Game) invincible() bool {
+	return g.console.godMode
+}
+
+// drawConsole draws the open console, or PAUSED while the game is paused.
+func (g *Game) drawConsole(screen Canvas) {
+	c := &g.console
+	if !c.open {
+		if c.paused {
+			r := g.viewRect()
+			DrawTextWith(screen, "PAUSED", (r.Min.X+r.Max.X)/2, r.Min.Y+2, &TextOptions{Color: theme.Palette.Highlight, Align: AlignCenter})
+		}
+		return
+	}
+	w, sh := g.screenSize()
+	h := (consoleLines+1)*consoleLineHeight + 4
+	top := sh - h
+	fillRect(screen, 0, float64(top), float64(w), float64(h), consolePanel)
+	for i, line := range c.output {
+		DrawText(screen, line, 2, top+2+(consoleLines-len(c.output)+i)*consoleLineHeight, theme.Palette.Text)
+	}
+	DrawText(screen, "> "+c.input+"_", 2, top+2+consoleLines*consoleLineHeight, theme.Palette.Highlight)
+}
+
+// consoleHelp lists the console commands.
+const consoleHelp = `level N      start level N
+lives N      score N
+god          toggle invincibility
+tp X Y       teleport Pac-Man
+mode M [G]   chase/scatter all, or
+             frightened/eaten ghost G
+dots         clear all dots but one
+pause        step [N]`
+
+// runCommand runs one console command and returns what to print.
+func (g *Game) runCommand(line string) string {
+	fields := strings.Fields(strings.ToLower(line))
+	args := fields[1:]
+	ints := func(n int) ([]int, error) {
+		if len(args) != n {
+			return nil, fmt.Errorf("%s takes %d number(s)", fields[0], n)
+		}
+		out := make([]int, n)
+		for i, a := range args {
+			v, err := strconv.Atoi(a)
+			if err != nil {
+				return nil, fmt.Errorf("not a number: %s", a)
+			}
+			out[i] = v
+		}
+		return out, nil
+	}
+
+	switch fields[0] {
+	case "help", "?":
+		return consoleHelp
+	case "level", "lives", "score":
+		v, err := ints(1)
+		if err != nil {
+			return err.Error()
+		}
+		if v[0] < 1 && fields[0] != "score" || v[0] < 0 {
+			return fields[0] + " out of range"
+		}
+		g.ensureGame()
+		switch fields[0] {
+		case "level":
+			g.level = v[0]
+			g.startLevel()
+		case "lives":
+			g.lives = v[0]
+		case "score":
+			g.score = v[0]
+		}
+		return fmt.Sprintf("%s %d", fields[0], v[0])
+	case "god":
+		g.console.godMode = !g.console.godMode
+		return fmt.Sprintf("invincible: %t", g.console.godMode)
+	case "tp":
+		v, err := ints(2)
+		if err != nil {
+			return err.Error()
+		}
+		g.ensureGame() // a new game may bring a new maze
+		if !g.maze.IsPassable(v[0], v[1]) {
+			return fmt.Sprintf("tile %d,%d is not passable", v[0], v[1])
+		}
+		p := g.pacman
+		p.X = float64(v[0]*TileSize + TileSize/2)
+		p.Y = float64(v[1]*TileSize + TileSize/2)
+		p.lastCenterTX, p.lastCenterTY = -1, -1
+		return fmt.Sprintf("pac-man at %d,%d", v[0], v[1])
+	case "mode":
+		return g.forceMode(args)
+	case "dots":
+		g.ensureGame()
+		n := g.clearDotsButOne()
+		return fmt.Sprintf("cleared %d dots", n)
+	case "pause":
+		g.console.paused = !g.console.paused
+		return fmt.Sprintf("paused: %t", g.console.paused)
+	case "step":
+		if !g.console.paused {
+			return "pause first"
+		}
+		n := 1
+		if len(args) > 0 {
+			v, err := ints(1)
+			if err != nil {
+				return err.Error()
+			}
+			n = max(v[0], 1)
+		}
+		g.console.steps += n
+		return ""
+	}
+	return "unknown command " + fields[0] + ", try help"
+}
+
+// ensureGame starts a one-player game if none is running, so cheats have a
+// game to change.
+func (g *Game) ensureGame() {
+	if !g.inGame() {
+		g.startGame(time.Now().UnixNano(), 1)
+	}
+}
+
+// forceMode runs "mode <mode> [ghost]". Chase and scatter replace the mode
+// timer for the rest of the level, as every ghost outside frightened or
+// eaten mode follows it. Frightened and eaten apply to one ghost or all.
+func (g *Game) forceMode(args []string) string {
+	if len(args) == 0 || len(args) > 2 {
+		return "mode chase|scatter|frightened|eaten [ghost]"
+	}
+	var mode GhostMode
+	found := false
+	for m := GhostChase; m <= GhostEaten; m++ {
+		if m.String() == args[0] {
+			mode, found = m, true
+		}
+	}
+	if !found {
+		return "unknown mode " + args[0]
+	}
+	g.ensureGame()
+
+	if mode == GhostChase || mode == GhostScatter {
+		if len(args) > 1 {
+			return "chase and scatter apply to all ghosts"
+		}
+		g.modeTimer = &ModeTimer{phases: []modePhase{{mode, -1}}}
+		for _, ghost := range g.ghosts {
+			if ghost.Mode != GhostFrightened && ghost.Mode != GhostEaten {
+				ghost.Mode = mode
+			}
+		}
+		return "all ghosts " + mode.String()
+	}
+
+	targets := g.ghosts[:]
+	if len(args) == 2 {
+		id, ok := ghostByName(args[1])
+		if !ok {
+			return "unknown ghost " + args[1]
+		}
+		targets = []*Ghost{g.ghosts[id]}
+	}
+	if mode == GhostFrightened && g.frightenedTimer == 0 {
+		// Without the timer running, frightened ghosts would never recover
+		d := g.difficulty()
+		g.frightenedTimer = d.FrightenedTicks
+		g.flashCount = d.FlashCount
+	}
+	for _, ghost := range targets {
+		if ghost.InHouse {
+			continue
+		}
+		if mode == GhostFrightened && ghost.Mode != GhostFrightened {
+			ghost.Reverse(g.maze)
+		}
+		ghost.Mode = mode
+	}
+	return fmt.Sprintf("%d ghost(s) %s", len(targets), mode)
+}
+
+// ghostByName finds a ghost by its lowercase name.
+func ghostByName(name string) (GhostID, bool) {
+	for id, n := range []string{"blinky", "pinky", "inky", "clyde"} {
+		if n == name {
+			return GhostID(id), true
+		}
+	}
+	return 0, false
+}
+
+// clearDotsButOne eats every dot and power pellet except the last one in
+// reading order, without scoring them, and returns how many were eaten.
+func (g *Game) clearDotsButOne() int {
+	n := 0
+	for y := 0; y < g.maze.Height; y++ {
+		for x := 0; x < g.maze.Width; x++ {
+			if g.maze.RemainingDots() > 1 && g.maze.ConsumeDot(x, y) {
+				n++
+			}
+		}
+	}
+	return n
+}
@@ -0,0 +1,11 @@
+//go:build !debug
+
+package game
+
+// debugConsole is the developer console, compiled in only with the debug
+// build tag; see console.go.
+type debugConsole struct{}
+
+func (g *Game) updateConsole() bool { return false }
+func (g *Game) drawConsole(Canvas)  {}
+func (g *Game) invincible() bool    { return false }
@@ -0,0 +1,112 @@
+//go:build debug
+
+package game
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestConsoleLevelLivesScore(t *testing.T) {
+	g := New()
+	g.runCommand("level 10")
+	if g.level != 10 || g.state != StateReady {
+		t.Errorf("level 10: got level %d, state %v", g.level, g.state)
+	}
+	if want := GetDifficulty(10).GhostSpeed; g.ghosts[0].Speed != want {
+		t.Errorf("ghost speed: got %v, want level 10's %v", g.ghosts[0].Speed, want)
+	}
+	g.runCommand("lives 5")
+	g.runCommand("score 9990")
+	if g.lives != 5 || g.score != 9990 {
+		t.Errorf("got lives %d, score %d", g.lives, g.score)
+	}
+	if out := g.runCommand("lives 0"); !strings.Contains(out, "out of range") || g.lives != 5 {
+		t.Errorf("lives 0: got %q, lives %d", out, g.lives)
+	}
+}
+
+func TestConsoleGodMode(t *testing.T) {
+	g := New()
+	g.startGame(1, 1)
+	g.state = StatePlaying
+	g.runCommand("god")
+	ghost := g.ghosts[Blinky]
+	g.pacman.X, g.pacman.Y = ghost.X, ghost.Y
+	g.checkGhostCollisions()
+	if !g.pacman.Alive || g.lives != 3 {
+		t.Error("an invincible Pac-Man should survive touching a ghost")
+	}
+	// Frightened ghosts can still be eaten
+	ghost.Mode = GhostFrightened
+	g.checkGhostCollisions()
+	if ghost.Mode != GhostEaten {
+		t.Errorf("frightened ghost: got mode %v, want eaten", ghost.Mode)
+	}
+}
+
+func TestConsoleTeleportAndDots(t *testing.T) {
+	g := New()
+	if out := g.runCommand("tp 0 0"); !strings.Contains(out, "not passable") {
+		t.Errorf("teleport into a wall: got %q", out)
+	}
+	g.runCommand("tp 6 5")
+	if g.pacman.TileX() != 6 || g.pacman.TileY() != 5 {
+		t.Errorf("teleport: Pac-Man at %d,%d, want 6,5", g.pacman.TileX(), g.pacman.TileY())
+	}
+	g.runCommand("dots")
+	if g.maze.RemainingDots() != 1 || g.score != 0 {
+		t.Errorf("got %d dots left and score %d, want 1 and 0", g.maze.RemainingDots(), g.score)
+	}
+}
+
+func TestConsoleTeleportOnNewGamesMaze(t *testing.T) {
+	// The game tp starts is played on a custom board with a wall at 6,5
+	layout := slices.Clone(mazeLayout)
+	layout[5] = layout[5][:6] + "#" + layout[5][7:]
+	g := New()
+	g.board = NewMazeFromLayout(layout)
+	if out := g.runCommand("tp 6 5"); !strings.Contains(out, "not passable") {
+		t.Errorf("teleport into the new maze's wall: got %q", out)
+	}
+}
+
+func TestConsoleForceMode(t *testing.T) {
+	g := New()
+	g.runCommand("mode chase")
+	for range 2000 {
+		g.modeTimer.Tick()
+	}
+	if g.modeTimer.CurrentMode() != GhostChase {
+		t.Error("forced chase should last the rest of the level")
+	}
+
+	g.runCommand("mode frightened blinky")
+	if g.ghosts[Blinky].Mode != GhostFrightened || g.ghosts[Clyde].Mode == GhostFrightened {
+		t.Error("only Blinky should be frightened")
+	}
+	if g.frightenedTimer == 0 {
+		t.Error("forcing frightened mode should start the frightened timer")
+	}
+	if out := g.runCommand("mode scatter inky"); !strings.Contains(out, "all ghosts") {
+		t.Errorf("scatter for one ghost: got %q", out)
+	}
+	if out := g.runCommand("mode sleepy"); !strings.Contains(out, "unknown mode") {
+		t.Errorf("bad mode: got %q", out)
+	}
+}
+
+func TestConsolePauseAndStep(t *testing.T) {
+	g := New()
+	if out := g.runCommand("step"); out != "pause first" {
+		t.Errorf("step while running: got %q", out)
+	}
+	g.runCommand("pause")
+	g.runCommand("step 2")
+	for i, want := range []bool{false, false, true} {
+		if got := g.updateConsole(); got != want {
+			t.Errorf("tick %d: skip %v, want %v", i, got, want)
+		}
+	}
+}
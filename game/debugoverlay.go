@@ -0,0 +1,138 @@
+package game
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// Debug overlay colors for tiles that block everyone or only Pac-Man, and
+// for the way one-way doors let actors through.
+var (
+	debugGrid      = color.RGBA{0x30, 0x30, 0x30, 0x30}
+	debugWall      = color.RGBA{0x40, 0x00, 0x00, 0x40}
+	debugGhostOnly = color.RGBA{0x00, 0x40, 0x40, 0x40}
+	debugOneWay    = color.RGBA{0xC0, 0xC0, 0xC0, 0xC0}
+	debugPanel     = color.RGBA{0x00, 0x00, 0x00, 0xC0}
+)
+
+// debugPixel is a white pixel, scaled and tinted to draw the overlay's
+// rectangles and lines.
+var debugPixel *ebiten.Image
+
+// SetDebugOverlay shows or hides the debug overlay. F3 toggles it.
+func (g *Game) SetDebugOverlay(on bool) {
+	g.debugOverlay = on
+}
+
+// updateDebugOverlayKey toggles the debug overlay on F3.
+func (g *Game) updateDebugOverlayKey() {
+	if inpututil.IsKeyJustPressed(ebiten.KeyF3) {
+		g.debugOverlay = !g.debugOverlay
+	}
+}
+
+// fillRect draws a filled rectangle. Colors are premultiplied, as color.RGBA
+// always is.
+func fillRect(screen Canvas, x, y, w, h float64, c color.Color) {
+	if debugPixel == nil {
+		px := newRGBA(1, 1)
+		px.Pix = []uint8{0xFF, 0xFF, 0xFF, 0xFF}
+		debugPixel = newImage(px)
+	}
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(w, h)
+	op.GeoM.Translate(x, y)
+	op.ColorScale.ScaleWithColor(c)
+	screen.DrawImage(debugPixel, op)
+}
+
+// drawDebugOverlay draws the AI and timing information over the frame: tile
+// passability and grid, each ghost's target tile and last decision, and a
+// panel with the mode timer, frightened timer and frame rates.
+func (g *Game) drawDebugOverlay(screen Canvas) {
+	left, top := g.toScreen(0, 0)
+
+	// Tint tiles by who cannot enter: red for everyone, cyan for Pac-Man.
+	// Pac-Man and active ghosts move by the same rules; only ghosts leaving
+	// the house, and eaten ghosts going back to it, cross its door.
+	for y := 0; y < g.maze.Height; y++ {
+		for x := 0; x < g.maze.Width; x++ {
+			var c color.Color
+			switch t := g.maze.TileAt(x, y); {
+			case t == TileGhostHouse || t == TileGhostDoor:
+				c = debugGhostOnly
+			case !g.maze.IsPassable(x, y):
+				c = debugWall
+			default:
+				continue
+			}
+			fillRect(screen, left+float64(x*TileSize), top+float64(y*TileSize), TileSize, TileSize, c)
+		}
+	}
+	// One-way doors, as a line from the center the way they can be crossed
+	for p, d := range g.maze.oneWay {
+		cx := left + float64(p.X*TileSize+TileSize/2)
+		cy := top + float64(p.Y*TileSize+TileSize/2)
+		drawDirLine(screen, cx, cy, d, debugOneWay)
+	}
+	for x := 0; x <= g.maze.Width; x++ {
+		fillRect(screen, left+float64(x*TileSize), top, 1, float64(g.maze.Height*TileSize), debugGrid)
+	}
+	for y := 0; y <= g.maze.Height; y++ {
+		fillRect(screen, left, top+float64(y*TileSize), float64(g.maze.Width*TileSize), 1, debugGrid)
+	}
+
+	for _, ghost := range g.ghosts {
+		if ghost.InHouse || ghost.lastDecisionTX < 0 {
+			continue
+		}
+		c := theme.Palette.Ghosts[ghost.ID]
+		if ghost.hasTarget {
+			// Crosshair on the target tile
+			cx := left + float64(ghost.targetTX*TileSize+TileSize/2)
+			cy := top + float64(ghost.targetTY*TileSize+TileSize/2)
+			fillRect(screen, cx-5, cy, 11, 1, c)
+			fillRect(screen, cx, cy-5, 1, 11, c)
+		}
+		// The direction chosen at the last tile center, as a line from it
+		cx := left + float64(ghost.lastDecisionTX*TileSize+TileSize/2)
+		cy := top + float64(ghost.lastDecisionTY*TileSize+TileSize/2)
+		fillRect(screen, cx-1, cy-1, 3, 3, c)
+		drawDirLine(screen, cx, cy, ghost.decisionDir, c)
+	}
+
+	phase, phases := g.modeTimer.Phase()
+	remaining := "FOREVER"
+	if r := g.modeTimer.Remaining(); r >= 0 {
+		remaining = fmt.Sprintf("%d", r)
+	}
+	lines := []string{
+		fmt.Sprintf("%s %d/%d %s", strings.ToUpper(g.modeTimer.CurrentMode().String()), phase+1, phases, remaining),
+		fmt.Sprintf("FRIGHT %d", g.frightenedTimer),
+		fmt.Sprintf("FPS %.1f TPS %.1f", ebiten.ActualFPS(), ebiten.ActualTPS()),
+	}
+	lineHeight := fontHeight + 2
+	r := g.viewRect()
+	fillRect(screen, float64(r.Min.X), float64(r.Min.Y), 112, float64(len(lines)*lineHeight+2), debugPanel)
+	for i, line := range lines {
+		DrawText(screen, line, r.Min.X+2, r.Min.Y+2+i*lineHeight, theme.Palette.Text)
+	}
+}
+
+// drawDirLine draws a tile-long line from x, y in direction d.
+func drawDirLine(screen Canvas, x, y float64, d Direction, c color.Color) {
+	switch d {
+	case DirUp:
+		fillRect(screen, x, y-TileSize, 1, TileSize, c)
+	case DirDown:
+		fillRect(screen, x, y, 1, TileSize, c)
+	case DirLeft:
+		fillRect(screen, x-TileSize, y, TileSize, 1, c)
+	case DirRight:
+		fillRect(screen, x, y, TileSize, 1, c)
+	}
+}
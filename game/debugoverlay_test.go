@@ -0,0 +1,51 @@
+package game
+
+import (
+	"image"
+	"testing"
+)
+
+func TestDebugOverlayMarksTarget(t *testing.T) {
+	g := New()
+	g.state = StatePlaying
+	UpdateGhost(g.ghosts[Blinky], g.maze, g.pacman, GhostScatter)
+	plain := g.Frame()
+
+	g.SetDebugOverlay(true)
+	frame := g.Frame()
+	// x = 96 is on a grid line
+	if frame.RGBAAt(96, 200) == plain.RGBAAt(96, 200) {
+		t.Error("the tile grid should be drawn")
+	}
+
+	// Blinky's scatter corner is in the top right wall; the crosshair is
+	// drawn over it in Blinky's color
+	b := g.ghosts[Blinky]
+	x := b.targetTX*TileSize + TileSize/2
+	y := HUDTopRows*TileSize + b.targetTY*TileSize + TileSize/2
+	if got, want := frame.RGBAAt(x, y), theme.Palette.Ghosts[Blinky]; got != want {
+		t.Errorf("crosshair at %d,%d: got %v, want %v", x, y, got, want)
+	}
+}
+
+func TestDebugOverlayMarksOneWayDoors(t *testing.T) {
+	layout := openLayout(28, 31)
+	layout[20] = layout[20][:10] + ">" + layout[20][11:]
+	g := New()
+	g.setMaze(NewMazeFromLayout(layout))
+	g.state = StatePlaying
+	plain := g.Frame()
+	g.SetDebugOverlay(true)
+	frame := g.Frame()
+
+	// The door at 10,20 opens to the right: the line runs right of its center
+	x, y := g.toScreen(10*TileSize+TileSize/2, 20*TileSize+TileSize/2)
+	right := image.Pt(int(x)+3, int(y))
+	left := image.Pt(int(x)-3, int(y))
+	if frame.RGBAAt(right.X, right.Y) == plain.RGBAAt(right.X, right.Y) {
+		t.Error("the one-way door's direction should be drawn")
+	}
+	if frame.RGBAAt(left.X, left.Y) != plain.RGBAAt(left.X, left.Y) {
+		t.Error("nothing should be drawn behind the one-way door")
+	}
+}
@@ -1,32 +1,245 @@
 package game
 
-// DifficultyParams holds all level-dependent game parameters.
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+// DifficultyParams holds all level-dependent game parameters: one row of a
+// difficulty table. Speeds are in pixels per tick.
 type DifficultyParams struct {
-	PacManSpeed     float64
-	GhostSpeed      float64
-	FrightenedSpeed float64
-	FrightenedTicks int // at 60 TPS
-	EatenSpeed      float64
+	PacManSpeed           float64 `json:"pacman_speed"`
+	PacManFrightenedSpeed float64 `json:"pacman_frightened_speed"` // while ghosts are frightened
+	GhostSpeed            float64 `json:"ghost_speed"`
+	GhostTunnelSpeed      float64 `json:"ghost_tunnel_speed"` // ghosts slow down in the side tunnels
+	FrightenedSpeed       float64 `json:"frightened_speed"`
+	EatenSpeed            float64 `json:"eaten_speed"` // eyes returning to the ghost house
+
+	// Blinky turns "Cruise Elroy" and speeds up when this few dots are
+	// left, and faster still at Elroy2Dots. 0 dots turns a stage off.
+	Elroy1Dots  int     `json:"elroy1_dots"`
+	Elroy1Speed float64 `json:"elroy1_speed"`
+	Elroy2Dots  int     `json:"elroy2_dots"`
+	Elroy2Speed float64 `json:"elroy2_speed"`
+
+	FrightenedTicks int `json:"frightened_ticks"` // at 60 TPS; 0 only turns the ghosts around
+	FlashCount      int `json:"flash_count"`      // blue/white flashes at the end of frightened mode
+
+	// Schedule is the ticks of each scatter and chase phase, alternating and
+	// starting with scatter. The phase after the last one lasts forever.
+	Schedule []int `json:"schedule"`
+
+	Fruit Fruit `json:"fruit"` // bonus fruit of the level
 }
 
-// GetDifficulty returns interpolated difficulty parameters for the given level.
-// Parameters are linearly interpolated between level 1 and level 10, clamped at 10.
+// DifficultyTable lists the difficulty of each level: entry i is level i+1,
+// and the last entry repeats for every level after it.
+type DifficultyTable []DifficultyParams
+
+// At returns the difficulty of a level.
+func (t DifficultyTable) At(level int) DifficultyParams {
+	return t[min(max(level, 1), len(t))-1]
+}
+
+// Difficulty presets, chosen with the rules file's "difficulty" key.
+const (
+	DifficultyClassic = "classic" // this game's original curve
+	DifficultyArcade  = "arcade"  // the arcade's per-level table
+)
+
+// DifficultyPresets lists the difficulty presets.
+var DifficultyPresets = []string{DifficultyClassic, DifficultyArcade}
+
+// DifficultyPreset returns a new copy of a preset's table, which the caller
+// may change.
+func DifficultyPreset(name string) (DifficultyTable, error) {
+	switch name {
+	case DifficultyClassic:
+		return classicTable(), nil
+	case DifficultyArcade:
+		return arcadeTable(), nil
+	}
+	return nil, fmt.Errorf("unknown difficulty %q (available: %s)", name, strings.Join(DifficultyPresets, ", "))
+}
+
+// GetDifficulty returns the classic difficulty parameters for the given level.
 func GetDifficulty(level int) DifficultyParams {
-	t := float64(level-1) / 9.0
-	if t < 0 {
-		t = 0
+	return classicTable().At(level)
+}
+
+// tableLevels is how many levels the presets list before the last entry
+// repeats.
+const tableLevels = 21
+
+// classicTable is the game's original difficulty: speeds and frightened time
+// interpolated linearly between level 1 and level 10, shorter scatter phases
+// each level down to 40%, no tunnel or Elroy slowdowns, and the arcade's
+// fruit.
+func classicTable() DifficultyTable {
+	t := make(DifficultyTable, tableLevels)
+	for i := range t {
+		level := i + 1
+		f := min(float64(level-1)/9.0, 1)
+		scatter := math.Max(0.4, 1.0-float64(level-1)*0.07)
+		ghost := lerp(1.3, 1.8, f)
+		pac := lerp(1.5, 1.8, f)
+		t[i] = DifficultyParams{
+			PacManSpeed:           pac,
+			PacManFrightenedSpeed: pac,
+			GhostSpeed:            ghost,
+			GhostTunnelSpeed:      ghost,
+			FrightenedSpeed:       0.8,
+			EatenSpeed:            3.0,
+			Elroy1Speed:           ghost,
+			Elroy2Speed:           ghost,
+			FrightenedTicks:       int(lerp(360, 60, f)),
+			FlashCount:            int(math.Round(lerp(5, 3, f))),
+			Schedule:              []int{int(420 * scatter), 1200, int(420 * scatter), 1200, int(300 * scatter)},
+			Fruit:                 arcadeFruit(level),
+		}
 	}
-	if t > 1 {
-		t = 1
+	return t
+}
+
+// arcadeFullSpeed is the arcade's 100% speed, 75.76 pixels per second, in
+// pixels per tick.
+const arcadeFullSpeed = 75.75757625 / 60
+
+// arcadeLevels is the arcade's difficulty table, speeds in percent of full
+// speed, from levels 1 to 21. Level 21 repeats.
+var arcadeLevels = []struct {
+	pac, pacFright, ghost, tunnel, fright  int
+	elroy1Dots, elroy1, elroy2Dots, elroy2 int
+	frightSeconds, flashes                 int
+}{
+	{80, 90, 75, 40, 50, 20, 80, 10, 85, 6, 5},
+	{90, 95, 85, 45, 55, 30, 90, 15, 95, 5, 5},
+	{90, 95, 85, 45, 55, 40, 90, 20, 95, 4, 5},
+	{90, 95, 85, 45, 55, 40, 90, 20, 95, 3, 5},
+	{100, 100, 95, 50, 60, 40, 100, 20, 105, 2, 5},
+	{100, 100, 95, 50, 60, 50, 100, 25, 105, 5, 5},
+	{100, 100, 95, 50, 60, 50, 100, 25, 105, 2, 5},
+	{100, 100, 95, 50, 60, 50, 100, 25, 105, 2, 5},
+	{100, 100, 95, 50, 60, 60, 100, 30, 105, 1, 3},
+	{100, 100, 95, 50, 60, 60, 100, 30, 105, 5, 5},
+	{100, 100, 95, 50, 60, 60, 100, 30, 105, 2, 5},
+	{100, 100, 95, 50, 60, 80, 100, 40, 105, 1, 3},
+	{100, 100, 95, 50, 60, 80, 100, 40, 105, 1, 3},
+	{100, 100, 95, 50, 60, 80, 100, 40, 105, 3, 5},
+	{100, 100, 95, 50, 60, 100, 100, 50, 105, 1, 3},
+	{100, 100, 95, 50, 60, 100, 100, 50, 105, 1, 3},
+	{100, 100, 95, 50, 60, 100, 100, 50, 105, 0, 0},
+	{100, 100, 95, 50, 60, 100, 100, 50, 105, 1, 3},
+	{100, 100, 95, 50, 60, 120, 100, 60, 105, 0, 0},
+	{100, 100, 95, 50, 60, 120, 100, 60, 105, 0, 0},
+	{90, 90, 95, 50, 60, 120, 100, 60, 105, 0, 0},
+}
+
+// arcadeTable builds the arcade preset from arcadeLevels. Eyes return at
+// twice full speed.
+func arcadeTable() DifficultyTable {
+	speed := func(percent int) float64 { return float64(percent) * arcadeFullSpeed / 100 }
+	t := make(DifficultyTable, len(arcadeLevels))
+	for i, l := range arcadeLevels {
+		level := i + 1
+		t[i] = DifficultyParams{
+			PacManSpeed:           speed(l.pac),
+			PacManFrightenedSpeed: speed(l.pacFright),
+			GhostSpeed:            speed(l.ghost),
+			GhostTunnelSpeed:      speed(l.tunnel),
+			FrightenedSpeed:       speed(l.fright),
+			EatenSpeed:            speed(200),
+			Elroy1Dots:            l.elroy1Dots,
+			Elroy1Speed:           speed(l.elroy1),
+			Elroy2Dots:            l.elroy2Dots,
+			Elroy2Speed:           speed(l.elroy2),
+			FrightenedTicks:       l.frightSeconds * 60,
+			FlashCount:            l.flashes,
+			Schedule:              arcadeSchedule(level),
+			Fruit:                 arcadeFruit(level),
+		}
+	}
+	return t
+}
+
+// arcadeSchedule returns the arcade's scatter and chase phases for a level.
+// From level 2 the fourth scatter lasts a single tick, barely turning the
+// ghosts around.
+func arcadeSchedule(level int) []int {
+	switch {
+	case level == 1:
+		return []int{7 * 60, 20 * 60, 7 * 60, 20 * 60, 5 * 60, 20 * 60, 5 * 60}
+	case level <= 4:
+		return []int{7 * 60, 20 * 60, 7 * 60, 20 * 60, 5 * 60, 1033 * 60, 1}
+	}
+	return []int{5 * 60, 20 * 60, 5 * 60, 20 * 60, 5 * 60, 1037 * 60, 1}
+}
+
+// arcadeFruit returns the arcade's bonus fruit for a level.
+func arcadeFruit(level int) Fruit {
+	switch {
+	case level <= 1:
+		return FruitCherries
+	case level == 2:
+		return FruitStrawberry
+	case level <= 4:
+		return FruitOrange
+	case level <= 6:
+		return FruitApple
+	case level <= 8:
+		return FruitMelon
+	case level <= 10:
+		return FruitGalaxian
+	case level <= 12:
+		return FruitBell
 	}
+	return FruitKey
+}
 
-	return DifficultyParams{
-		PacManSpeed:     lerp(1.5, 1.8, t),
-		GhostSpeed:      lerp(1.3, 1.8, t),
-		FrightenedSpeed: 0.8,
-		FrightenedTicks: int(lerp(360, 60, t)),
-		EatenSpeed:      3.0,
+// validate checks that speeds are within (0, maxSpeed], counts are not
+// negative, and the schedule's phases last at least a tick.
+func (p DifficultyParams) validate() error {
+	speeds := []struct {
+		name string
+		v    float64
+	}{
+		{"pacman_speed", p.PacManSpeed},
+		{"pacman_frightened_speed", p.PacManFrightenedSpeed},
+		{"ghost_speed", p.GhostSpeed},
+		{"ghost_tunnel_speed", p.GhostTunnelSpeed},
+		{"frightened_speed", p.FrightenedSpeed},
+		{"eaten_speed", p.EatenSpeed},
+		{"elroy1_speed", p.Elroy1Speed},
+		{"elroy2_speed", p.Elroy2Speed},
+	}
+	for _, s := range speeds {
+		if s.v <= 0 || s.v > maxSpeed {
+			return fmt.Errorf("%s must be above 0 and at most %d, got %g", s.name, maxSpeed, s.v)
+		}
+	}
+	counts := []struct {
+		name string
+		v    int
+	}{
+		{"elroy1_dots", p.Elroy1Dots},
+		{"elroy2_dots", p.Elroy2Dots},
+		{"frightened_ticks", p.FrightenedTicks},
+		{"flash_count", p.FlashCount},
+	}
+	for _, c := range counts {
+		if c.v < 0 {
+			return fmt.Errorf("%s must not be negative, got %d", c.name, c.v)
+		}
+	}
+	if p.Elroy2Dots > p.Elroy1Dots {
+		return fmt.Errorf("elroy2_dots (%d) must not be more than elroy1_dots (%d)", p.Elroy2Dots, p.Elroy1Dots)
+	}
+	if slices.ContainsFunc(p.Schedule, func(ticks int) bool { return ticks <= 0 }) {
+		return fmt.Errorf("schedule phases must last at least one tick, got %v", p.Schedule)
 	}
+	return nil
 }
 
 func lerp(a, b, t float64) float64 {
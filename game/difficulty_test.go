@@ -1,6 +1,9 @@
 package game
 
-import "testing"
+import (
+	"math"
+	"testing"
+)
 
 func TestDifficultyLevel1(t *testing.T) {
 	d := GetDifficulty(1)
@@ -44,3 +47,160 @@ func TestDifficultyCapsAtLevel10(t *testing.T) {
 		t.Error("difficulty should cap at level 10")
 	}
 }
+
+func TestDifficultyFlashCount(t *testing.T) {
+	if d := GetDifficulty(1); d.FlashCount != 5 {
+		t.Errorf("level 1 flash count: got %d, want 5", d.FlashCount)
+	}
+	if d := GetDifficulty(10); d.FlashCount != 3 {
+		t.Errorf("level 10 flash count: got %d, want 3", d.FlashCount)
+	}
+}
+
+func TestFrightenedFlashing(t *testing.T) {
+	g := New()
+	g.triggerFrightenedMode()
+	flashTicks := g.flashCount * 2 * FrightenedFlashTicks
+
+	g.frightenedTimer = flashTicks + 1
+	if g.frightenedFlashWhite() {
+		t.Error("ghosts should stay blue before the final flashes")
+	}
+	g.frightenedTimer = flashTicks
+	if !g.frightenedFlashWhite() {
+		t.Error("first flash should start white")
+	}
+	g.frightenedTimer = flashTicks - FrightenedFlashTicks
+	if g.frightenedFlashWhite() {
+		t.Error("second half of a flash should be blue")
+	}
+}
+
+func TestArcadeTable(t *testing.T) {
+	table, err := DifficultyPreset(DifficultyArcade)
+	if err != nil {
+		t.Fatal(err)
+	}
+	near := func(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+	d1 := table.At(1)
+	if !near(d1.PacManSpeed, 0.8*arcadeFullSpeed) || !near(d1.GhostTunnelSpeed, 0.4*arcadeFullSpeed) {
+		t.Errorf("level 1: got pac-man %v, tunnel %v; want 80%% and 40%% of full speed", d1.PacManSpeed, d1.GhostTunnelSpeed)
+	}
+	if d1.FrightenedTicks != 360 || d1.Elroy1Dots != 20 || d1.Elroy2Dots != 10 || d1.Fruit != FruitCherries {
+		t.Errorf("level 1: got %+v", d1)
+	}
+	if d := table.At(17); d.FrightenedTicks != 0 || d.FlashCount != 0 {
+		t.Errorf("level 17 has no frightened time, got %d ticks", d.FrightenedTicks)
+	}
+	d21 := table.At(21)
+	if d := table.At(40); !near(d.PacManSpeed, d21.PacManSpeed) || d.Fruit != FruitKey || d.Elroy1Dots != 120 {
+		t.Errorf("level 21 should repeat, got %+v", d)
+	}
+	if !near(d21.PacManSpeed, 0.9*arcadeFullSpeed) {
+		t.Errorf("level 21 pac-man speed: got %v, want 90%%", d21.PacManSpeed)
+	}
+	for level := 1; level <= tableLevels; level++ {
+		if err := table.At(level).validate(); err != nil {
+			t.Errorf("level %d: %v", level, err)
+		}
+	}
+}
+
+func TestPresetFruit(t *testing.T) {
+	want := map[int]Fruit{1: FruitCherries, 2: FruitStrawberry, 4: FruitOrange, 5: FruitApple, 8: FruitMelon, 9: FruitGalaxian, 12: FruitBell, 13: FruitKey, 50: FruitKey}
+	for _, name := range DifficultyPresets {
+		table, _ := DifficultyPreset(name)
+		for level, fruit := range want {
+			if got := table.At(level).Fruit; got != fruit {
+				t.Errorf("%s level %d: got %v, want %v", name, level, got, fruit)
+			}
+		}
+	}
+}
+
+func TestModeTimerSchedule(t *testing.T) {
+	mt := NewModeTimer(arcadeSchedule(5))
+	want := []struct {
+		mode  GhostMode
+		ticks int
+	}{
+		{GhostScatter, 300}, {GhostChase, 1200}, {GhostScatter, 300}, {GhostChase, 1200},
+		{GhostScatter, 300}, {GhostChase, 1037 * 60}, {GhostScatter, 1},
+	}
+	for i, phase := range want {
+		if mt.CurrentMode() != phase.mode || mt.Remaining() != phase.ticks {
+			t.Fatalf("phase %d: got %v for %d ticks, want %v for %d", i, mt.CurrentMode(), mt.Remaining(), phase.mode, phase.ticks)
+		}
+		for range phase.ticks {
+			mt.Tick()
+		}
+	}
+	if mt.CurrentMode() != GhostChase || mt.Remaining() != -1 {
+		t.Errorf("after the schedule: got %v for %d ticks, want chase forever", mt.CurrentMode(), mt.Remaining())
+	}
+	if mt := NewModeTimer([]int{60, 60}); mt.phases[2].mode != GhostScatter {
+		t.Error("an even schedule should end in scatter forever")
+	}
+}
+
+func TestGhostSpeeds(t *testing.T) {
+	table, _ := DifficultyPreset(DifficultyArcade)
+	d := table.At(1)
+	m := NewMaze()
+	blinky := NewGhosts()[Blinky]
+	place := func(x, y int) {
+		blinky.X, blinky.Y = float64(x*TileSize+TileSize/2), float64(y*TileSize+TileSize/2)
+	}
+	place(6, 5)
+	if got := ghostSpeed(d, blinky, m); got != d.GhostSpeed {
+		t.Errorf("normal: got %v, want %v", got, d.GhostSpeed)
+	}
+	place(2, 14)
+	if got := ghostSpeed(d, blinky, m); got != d.GhostTunnelSpeed {
+		t.Errorf("tunnel: got %v, want %v", got, d.GhostTunnelSpeed)
+	}
+	place(6, 5)
+	blinky.Mode = GhostFrightened
+	if got := ghostSpeed(d, blinky, m); got != d.FrightenedSpeed {
+		t.Errorf("frightened: got %v, want %v", got, d.FrightenedSpeed)
+	}
+	blinky.Mode = GhostEaten
+	if got := ghostSpeed(d, blinky, m); got != d.EatenSpeed {
+		t.Errorf("eaten: got %v, want %v", got, d.EatenSpeed)
+	}
+
+	blinky.Mode = GhostChase
+	clearDots := func(left int) {
+		for y := 0; y < m.Height && m.RemainingDots() > left; y++ {
+			for x := 0; x < m.Width && m.RemainingDots() > left; x++ {
+				m.ConsumeDot(x, y)
+			}
+		}
+	}
+	clearDots(d.Elroy1Dots)
+	if got := ghostSpeed(d, blinky, m); got != d.Elroy1Speed {
+		t.Errorf("Elroy 1: got %v, want %v", got, d.Elroy1Speed)
+	}
+	clearDots(d.Elroy2Dots)
+	if got := ghostSpeed(d, blinky, m); got != d.Elroy2Speed {
+		t.Errorf("Elroy 2: got %v, want %v", got, d.Elroy2Speed)
+	}
+	if pinky := NewGhosts()[Pinky]; ghostSpeed(d, pinky, m) != d.GhostSpeed {
+		t.Error("only Blinky speeds up when the dots run out")
+	}
+}
+
+func TestNoFrightenedTimeOnlyReverses(t *testing.T) {
+	r := DefaultRules()
+	r.Difficulty = DifficultyArcade
+	g := New()
+	g.SetRules(r)
+	g.startGame(1, 1)
+	g.level = 17
+	blinky := g.ghosts[Blinky]
+	before := blinky.Dir
+	g.triggerFrightenedMode()
+	if blinky.Mode == GhostFrightened || blinky.Dir != reverseDir(before) {
+		t.Errorf("got mode %v, dir %v; want Blinky turned around but not frightened", blinky.Mode, blinky.Dir)
+	}
+}
@@ -0,0 +1,217 @@
+package game
+
+import (
+	"embed"
+	"math"
+	"path"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// Window size bounds, as multiples of the game screen.
+const (
+	DefaultWindowScale = 3
+	MaxWindowScale     = 6
+)
+
+// Screen filters applied when the game screen is scaled up to the window.
+const (
+	FilterNone      = "none"
+	FilterScanlines = "scanlines"
+	FilterCRT       = "crt"
+)
+
+// Orientations lists the display rotations, in degrees clockwise.
+var Orientations = []int{0, 90, 180, 270}
+
+// ScreenFilters lists the screen filters in settings screen order.
+var ScreenFilters = []string{FilterNone, FilterScanlines, FilterCRT}
+
+// filterNames maps each screen filter to its message ID.
+var filterNames = map[string]string{
+	FilterNone:      msgFilterNone,
+	FilterScanlines: msgFilterScanlines,
+	FilterCRT:       msgFilterCRT,
+}
+
+//go:embed shaders/*.kage
+var shaderFiles embed.FS
+
+// filterShaders caches the compiled shader of each filter but FilterNone.
+var filterShaders = map[string]*ebiten.Shader{}
+
+// filterShader returns the compiled Kage shader for a screen filter, compiling
+// it on first use. The shaders are compiled into the binary, so one that does
+// not compile is a programming error.
+func filterShader(filter string) *ebiten.Shader {
+	if s, ok := filterShaders[filter]; ok {
+		return s
+	}
+	src, err := shaderFiles.ReadFile(path.Join("shaders", filter+".kage"))
+	if err != nil {
+		panic(err)
+	}
+	s, err := ebiten.NewShader(src)
+	if err != nil {
+		panic("shader " + filter + ": " + err.Error())
+	}
+	filterShaders[filter] = s
+	return s
+}
+
+// Layout makes the screen as large as the window in device pixels, so the
+// game screen can be scaled to whole pixels by present.
+func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
+	s := ebiten.Monitor().DeviceScaleFactor()
+	g.windowW, g.windowH = int(math.Ceil(float64(outsideWidth)*s)), int(math.Ceil(float64(outsideHeight)*s))
+	return g.windowW, g.windowH
+}
+
+// letterbox returns how to fit a fw x fh picture centered in a w x h window:
+// scaled by the largest whole factor that fits, with black bars around it.
+// Windows smaller than the picture get a fractional scale instead.
+func letterbox(w, h, fw, fh int) (scale, x, y float64) {
+	scale = float64(min(w/fw, h/fh))
+	if scale < 1 {
+		scale = min(float64(w)/float64(fw), float64(h)/float64(fh))
+	}
+	x = math.Floor((float64(w) - float64(fw)*scale) / 2)
+	y = math.Floor((float64(h) - float64(fh)*scale) / 2)
+	return scale, x, y
+}
+
+// screenSize returns the size of the game screen: the view onto the maze with
+// the HUD rows above and below it and the minimap to its right, but no
+// smaller than MinScreenWidth x MinScreenHeight.
+func (g *Game) screenSize() (w, h int) {
+	vw, vh := g.viewSize()
+	return max(vw+g.minimapWidth(), MinScreenWidth), max(vh+(HUDTopRows+HUDBotRows)*TileSize, MinScreenHeight)
+}
+
+// menuOrigin returns the top left corner of the MinScreenWidth x
+// MinScreenHeight area that the title and settings screens are laid out in,
+// centered on larger screens.
+func (g *Game) menuOrigin() (x, y int) {
+	w, h := g.screenSize()
+	return (w - MinScreenWidth) / 2, (h - MinScreenHeight) / 2
+}
+
+// rotatedSize returns the size of a w x h game screen turned by degrees.
+func rotatedSize(degrees, w, h int) (int, int) {
+	if degrees%180 != 0 {
+		return h, w
+	}
+	return w, h
+}
+
+// displayRotation returns how far the game screen is turned on the display:
+// the orientation setting, plus half a turn during player two's turns in
+// cocktail mode so the screen faces them across the table.
+func (g *Game) displayRotation() int {
+	r := g.settings.Orientation
+	if g.settings.Cocktail && g.players == 2 && g.current == 1 && g.inGame() {
+		r += 180
+	}
+	return r % 360
+}
+
+// present draws the finished game frame rotated and scaled up onto the
+// window, through the screen filter's shader if one is selected.
+func (g *Game) present(screen, frame *ebiten.Image) {
+	b := screen.Bounds()
+	geo := g.presentGeoM(b.Dx(), b.Dy())
+
+	if g.settings.ScreenFilter == FilterNone {
+		screen.DrawImage(frame, &ebiten.DrawImageOptions{GeoM: geo})
+		return
+	}
+	op := &ebiten.DrawRectShaderOptions{GeoM: geo}
+	op.Images[0] = frame
+	fb := frame.Bounds()
+	screen.DrawRectShader(fb.Dx(), fb.Dy(), filterShader(g.settings.ScreenFilter), op)
+}
+
+// presentGeoM returns how present places the game screen in a w x h
+// window: turned by the display rotation, scaled and letterboxed.
+func (g *Game) presentGeoM(w, h int) ebiten.GeoM {
+	rot := g.displayRotation()
+	sw, sh := g.screenSize()
+	rw, rh := rotatedSize(rot, sw, sh)
+	scale, x, y := letterbox(w, h, rw, rh)
+	var geo ebiten.GeoM
+	geo.Translate(-float64(sw)/2, -float64(sh)/2)
+	geo.Rotate(float64(rot) * math.Pi / 180)
+	geo.Translate(float64(rw)/2, float64(rh)/2)
+	geo.Scale(scale, scale)
+	geo.Translate(x, y)
+	return geo
+}
+
+// cursorPosition returns the mouse position on the game screen, undoing
+// present's rotation and scaling. ok is false before the first Layout.
+func (g *Game) cursorPosition() (x, y float64, ok bool) {
+	if g.windowW == 0 || g.windowH == 0 {
+		return 0, 0, false
+	}
+	geo := g.presentGeoM(g.windowW, g.windowH)
+	geo.Invert()
+	cx, cy := ebiten.CursorPosition()
+	x, y = geo.Apply(float64(cx), float64(cy))
+	return x, y, true
+}
+
+// ApplyWindowSettings sets the window size, fullscreen, vsync and resizing
+// from the settings. Call it once before ebiten.RunGame; the settings screen
+// applies its own changes.
+func (g *Game) ApplyWindowSettings() {
+	s := g.settings
+	g.windowed = true
+	g.applyWindowSize()
+	ebiten.SetFullscreen(s.Fullscreen)
+	ebiten.SetVsyncEnabled(s.VSync)
+	applyResizable(s.Resizable)
+}
+
+// applyWindowSize sizes the window for the window scale, the orientation
+// and the size of the maze.
+func (g *Game) applyWindowSize() {
+	sw, sh := g.screenSize()
+	w, h := rotatedSize(g.settings.Orientation, sw, sh)
+	ebiten.SetWindowSize(w*g.settings.WindowScale, h*g.settings.WindowScale)
+}
+
+func applyResizable(resizable bool) {
+	mode := ebiten.WindowResizingModeDisabled
+	if resizable {
+		mode = ebiten.WindowResizingModeEnabled
+	}
+	ebiten.SetWindowResizingMode(mode)
+}
+
+// updateFullscreenKey toggles fullscreen on Alt+Enter and saves the change.
+func (g *Game) updateFullscreenKey() {
+	if ebiten.IsKeyPressed(ebiten.KeyAlt) && inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
+		g.settings.Fullscreen = !g.settings.Fullscreen
+		ebiten.SetFullscreen(g.settings.Fullscreen)
+		g.saveSettings()
+	}
+}
+
+// validOrientation returns degrees if it is one of Orientations, else 0.
+func validOrientation(degrees int) int {
+	for _, o := range Orientations {
+		if o == degrees {
+			return o
+		}
+	}
+	return 0
+}
+
+// validFilter returns filter if it is a known screen filter, else FilterNone.
+func validFilter(filter string) string {
+	if _, ok := filterNames[filter]; ok {
+		return filter
+	}
+	return FilterNone
+}
@@ -0,0 +1,207 @@
+package game
+
+import (
+	"image"
+	"strings"
+	"testing"
+)
+
+func TestLetterbox(t *testing.T) {
+	tests := []struct {
+		w, h        int
+		scale, x, y float64
+	}{
+		{MinScreenWidth, MinScreenHeight, 1, 0, 0},
+		{3 * MinScreenWidth, 3 * MinScreenHeight, 3, 0, 0},
+		{1920, 1080, 3, 624, 108}, // 672x864, bars left and right
+		{800, 2000, 3, 64, 568},   // bars above and below
+		{1000, 870, 3, 164, 3},    // just too small for 4x in height
+		{112, 288, 0.5, 0, 72},    // smaller than the game: fractional
+		{MinScreenWidth + 1, 1000, 1, 0, 356},
+	}
+	for _, tt := range tests {
+		scale, x, y := letterbox(tt.w, tt.h, MinScreenWidth, MinScreenHeight)
+		if scale != tt.scale || x != tt.x || y != tt.y {
+			t.Errorf("letterbox(%d, %d) = %v, %v, %v; want %v, %v, %v", tt.w, tt.h, scale, x, y, tt.scale, tt.x, tt.y)
+		}
+	}
+}
+
+func TestLetterboxRotated(t *testing.T) {
+	// Turned a quarter, the 288x224 picture fits a 1920x1080 screen 4 times
+	w, h := rotatedSize(90, MinScreenWidth, MinScreenHeight)
+	scale, x, y := letterbox(1920, 1080, w, h)
+	if scale != 4 || x != 384 || y != 92 {
+		t.Errorf("got %v, %v, %v; want 4, 384, 92", scale, x, y)
+	}
+}
+
+func TestDisplayRotation(t *testing.T) {
+	tests := []struct {
+		orientation     int
+		cocktail        bool
+		players, player int
+		state           GameState
+		want            int
+	}{
+		{0, false, 1, 0, StatePlaying, 0},
+		{90, false, 1, 0, StatePlaying, 90},
+		{0, true, 2, 0, StatePlaying, 0},
+		{0, true, 2, 1, StatePlaying, 180},
+		{270, true, 2, 1, StateReady, 90},
+		{0, true, 2, 1, StateGameOver, 0}, // the game is over, face the cabinet again
+		{0, false, 2, 1, StatePlaying, 0},
+	}
+	for _, tt := range tests {
+		g := New()
+		g.settings.Orientation = tt.orientation
+		g.settings.Cocktail = tt.cocktail
+		g.players, g.current, g.state = tt.players, tt.player, tt.state
+		if got := g.displayRotation(); got != tt.want {
+			t.Errorf("%+v: got %d", tt, got)
+		}
+	}
+}
+
+func TestRotateDir(t *testing.T) {
+	tests := []struct {
+		d       Direction
+		degrees int
+		want    Direction
+	}{
+		{DirUp, 0, DirUp},
+		{DirUp, 90, DirRight},
+		{DirLeft, 90, DirUp},
+		{DirUp, -90, DirLeft},
+		{DirRight, -90, DirUp}, // right on a display turned 90 degrees is up in the maze
+		{DirDown, 180, DirUp},
+		{DirDown, -270, DirLeft},
+		{DirNone, 90, DirNone},
+	}
+	for _, tt := range tests {
+		if got := rotateDir(tt.d, tt.degrees); got != tt.want {
+			t.Errorf("rotateDir(%v, %d) = %v, want %v", tt.d, tt.degrees, got, tt.want)
+		}
+	}
+}
+
+func TestSettingsScroll(t *testing.T) {
+	rows := settingsVisibleRows + 5
+	tests := []struct {
+		cursor, rows, want int
+	}{
+		{0, rows, 0},
+		{settingsVisibleRows / 2, rows, 0},
+		{settingsVisibleRows/2 + 1, rows, 1},
+		{rows - 1, rows, 5},
+		{3, settingsVisibleRows - 2, 0}, // everything fits
+	}
+	for _, tt := range tests {
+		if got := settingsScroll(tt.cursor, tt.rows); got != tt.want {
+			t.Errorf("settingsScroll(%d, %d) = %d, want %d", tt.cursor, tt.rows, got, tt.want)
+		}
+	}
+}
+
+// smallMaze is a playable 9x6 board with a tunnel along row 4.
+var smallMaze = []string{
+	"#########",
+	"#.......#",
+	"#.##-##.#",
+	"#.#GGG#.#",
+	"=.......=",
+	"#########",
+}
+
+var smallSpawns = Spawns{
+	PacMan:  TilePos{4, 4},
+	Fruit:   TilePos{1, 1},
+	Ghosts:  [4]TilePos{{4, 1}, {3, 3}, {4, 3}, {5, 3}},
+	Scatter: [4]TilePos{{8, 0}, {0, 0}, {8, 5}, {0, 5}},
+}
+
+func TestScreenFitsMaze(t *testing.T) {
+	large := make([]string, 35)
+	for y := range large {
+		large[y] = strings.Repeat("#", 40)
+	}
+	tests := []struct {
+		name          string
+		maze          *Maze
+		width, height int
+		rect          image.Rectangle
+	}{
+		{"classic", NewMaze(), 224, 288, image.Rect(0, 24, 224, 272)},
+		{"small", newMaze(smallMaze, smallSpawns), 224, 288, image.Rect(76, 124, 148, 172)},
+		{"large", NewMazeFromLayout(large), 320, 320, image.Rect(0, 24, 320, 304)},
+		{"huge", NewMazeFromLayout(openLayout(100, 80)), 320, 320, image.Rect(0, 24, 320, 304)},
+	}
+	for _, tt := range tests {
+		g := New()
+		g.setMaze(tt.maze)
+		if w, h := g.screenSize(); w != tt.width || h != tt.height {
+			t.Errorf("%s: got a %dx%d screen, want %dx%d", tt.name, w, h, tt.width, tt.height)
+		}
+		if got := g.viewRect(); got != tt.rect {
+			t.Errorf("%s: got the view at %v, want %v", tt.name, got, tt.rect)
+		}
+		if got := g.hud().View; got != tt.rect {
+			t.Errorf("%s: got the HUD around %v, want %v", tt.name, got, tt.rect)
+		}
+	}
+}
+
+func TestSmallMazeWraps(t *testing.T) {
+	m := newMaze(smallMaze, smallSpawns)
+	if err := m.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	p := NewPacManAt(TilePos{0, 4})
+	p.Dir = DirLeft
+	for range 4 {
+		p.Move(m)
+	}
+	if p.TileX() != 8 {
+		t.Errorf("got Pac-Man at column %d, want 8 after wrapping around the 9-wide board", p.TileX())
+	}
+
+	g := NewGhostsAt(smallSpawns)[Blinky]
+	g.X, g.Y = float64(TileSize/2), float64(4*TileSize+TileSize/2)
+	g.Dir = DirLeft
+	for range 8 {
+		UpdateGhost(g, m, p, GhostChase)
+	}
+	if g.X < float64(6*TileSize) {
+		t.Errorf("got the ghost at x %.1f, want it wrapped to the right edge", g.X)
+	}
+}
+
+func TestLifeIconsCapped(t *testing.T) {
+	g := New()
+	g.state = StatePlaying
+	g.lives = 1
+	none := g.Frame()
+	g.lives = 20
+	frame := g.Frame()
+
+	// Five icons, then "x14" for the rest; nothing after that
+	v := g.viewRect()
+	textX := v.Min.X + 2 + maxLifeIcons*10
+	textEnd := textX + lineWidth("x14")
+	differs := func(x0, x1 int) bool {
+		for x := x0; x < x1; x++ {
+			for y := v.Max.Y; y < v.Max.Y+TileSize*2; y++ {
+				if frame.RGBAAt(x, y) != none.RGBAAt(x, y) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	if !differs(textX, textEnd) {
+		t.Error("the spare lives past the icons should be counted")
+	}
+	if differs(textEnd, v.Min.X+2+19*10) {
+		t.Error("only five life icons should be drawn")
+	}
+}
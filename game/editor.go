@@ -0,0 +1,485 @@
+package game
+
+import (
+	"cmp"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// Brushes of the maze editor, in the order Tab cycles through them. Tile
+// brushes paint tiles; the others place spawns.
+const (
+	brushWall = iota
+	brushDot
+	brushPowerPellet
+	brushEmpty
+	brushGhostHouse
+	brushGhostDoor
+	brushTunnel
+	brushPortal // paints the first digit without a pair, see portalTile
+	brushOneWay // points the way e.turn picks
+	brushPacMan
+	brushFruit
+	brushBlinky // and the other ghosts, by GhostID
+	brushPinky
+	brushInky
+	brushClyde
+	brushCount
+)
+
+// brushTiles are the layout characters the tile brushes paint, but for
+// portals and one-way doors.
+var brushTiles = [...]byte{brushWall: '#', brushDot: '.', brushPowerPellet: 'o', brushEmpty: ' ', brushGhostHouse: 'G', brushGhostDoor: '-', brushTunnel: '='}
+
+// brushNames are the brushes' message IDs.
+var brushNames = [brushCount]string{
+	msgBrushWall, msgBrushDot, msgBrushPowerPellet, msgBrushEmpty, msgBrushGhostHouse, msgBrushGhostDoor,
+	msgBrushTunnel, msgBrushPortal, msgBrushOneWay,
+	msgBrushPacMan, msgBrushFruit, msgBrushBlinky, msgBrushPinky, msgBrushInky, msgBrushClyde,
+}
+
+// oneWayTurns are the one-way doors in the order R turns the brush,
+// clockwise.
+const oneWayTurns = ">v<^"
+
+// defaultMazeFile is the file name the editor saves to when no maze file
+// was given with SetMazeFile.
+const defaultMazeFile = "go-pacman-maze.txt"
+
+// mazeEditor is the maze editor's board and history. The board is kept as
+// layout characters so undo can snapshot it cheaply.
+type mazeEditor struct {
+	layout  [][]byte
+	spawns  Spawns
+	brush   int
+	mirror  bool // paint tiles on both halves of the board
+	turn    int  // index in oneWayTurns of the one-way door brush
+	undo    []editorSnapshot
+	redo    []editorSnapshot
+	stroke  bool   // the mouse stroke in progress has an undo snapshot
+	path    string // where Ctrl+S saves
+	status  string // result of the last save or test play, shown at the top
+	testing bool   // a test game of the board is being played
+}
+
+// editorSnapshot is the board at one step of the undo history.
+type editorSnapshot struct {
+	layout []string
+	spawns Spawns
+}
+
+// load puts m's board in the editor, forgetting the history.
+func (e *mazeEditor) load(m *Maze) {
+	e.layout = make([][]byte, len(m.layout))
+	for y, row := range m.layout {
+		e.layout[y] = []byte(row)
+	}
+	e.spawns = m.Spawns
+	e.undo, e.redo = nil, nil
+}
+
+func (e *mazeEditor) snapshot() editorSnapshot {
+	s := editorSnapshot{layout: make([]string, len(e.layout)), spawns: e.spawns}
+	for y, row := range e.layout {
+		s.layout[y] = string(row)
+	}
+	return s
+}
+
+func (e *mazeEditor) restore(s editorSnapshot) {
+	for y, row := range s.layout {
+		e.layout[y] = []byte(row)
+	}
+	e.spawns = s.spawns
+}
+
+// maze returns the board being edited, ready to play.
+func (e *mazeEditor) maze() *Maze {
+	return newMaze(e.snapshot().layout, e.spawns)
+}
+
+// paint applies the brush to tile x, y, or with right the brush's other
+// use: tile brushes erase, ghost brushes place the scatter target. With
+// mirror on, tiles are painted on the mirror image across the vertical axis
+// too. The first change of each mouse stroke can be undone; paint reports
+// whether anything changed.
+func (e *mazeEditor) paint(x, y int, right bool) bool {
+	if y < 0 || y >= len(e.layout) || x < 0 || x >= len(e.layout[y]) {
+		return false
+	}
+	before := e.snapshot()
+	p := TilePos{x, y}
+	switch {
+	case e.brush < brushPacMan:
+		mx := len(e.layout[y]) - 1 - x
+		var ch byte
+		switch {
+		case right:
+			ch = brushTiles[brushEmpty]
+		case e.brush == brushPortal:
+			ch = e.portalTile(p, TilePos{mx, y})
+		case e.brush == brushOneWay:
+			ch = oneWayTurns[e.turn]
+		default:
+			ch = brushTiles[e.brush]
+		}
+		e.layout[y][x] = ch
+		if e.mirror {
+			e.layout[y][mx] = mirrorTile(ch)
+		}
+	case right && e.brush >= brushBlinky:
+		e.spawns.Scatter[e.brush-brushBlinky] = p
+	case right:
+		return false
+	case e.brush == brushPacMan:
+		e.spawns.PacMan = p
+	case e.brush == brushFruit:
+		e.spawns.Fruit = p
+	default:
+		e.spawns.Ghosts[e.brush-brushBlinky] = p
+	}
+	if e.spawns == before.spawns && slices.Equal(e.snapshot().layout, before.layout) {
+		return false
+	}
+	if !e.stroke {
+		e.undo = append(e.undo, before)
+		e.redo = nil
+		e.stroke = true
+	}
+	e.status = ""
+	return true
+}
+
+// portalTile returns the portal digit to paint at p, and at its mirror image
+// with mirror on: the first digit with one portal elsewhere, to complete its
+// pair, or the first unused one, so mirrored portals are a pair.
+func (e *mazeEditor) portalTile(p, mirrored TilePos) byte {
+	var count [10]int
+	for y, row := range e.layout {
+		for x, ch := range row {
+			at := TilePos{x, y}
+			if ch >= '0' && ch <= '9' && at != p && (!e.mirror || at != mirrored) {
+				count[ch-'0']++
+			}
+		}
+	}
+	if !e.mirror {
+		if i := slices.Index(count[:], 1); i >= 0 {
+			return byte('0' + i)
+		}
+	}
+	if i := slices.Index(count[:], 0); i >= 0 {
+		return byte('0' + i)
+	}
+	return '9'
+}
+
+// mirrorTile returns the tile painted at the mirror image of ch: one-way
+// doors across the board turn round.
+func mirrorTile(ch byte) byte {
+	switch ch {
+	case '<':
+		return '>'
+	case '>':
+		return '<'
+	}
+	return ch
+}
+
+// endStroke ends a mouse stroke, so the next change starts a new undo step.
+func (e *mazeEditor) endStroke() {
+	e.stroke = false
+}
+
+// undoStep undoes the last stroke, if any.
+func (e *mazeEditor) undoStep() {
+	if len(e.undo) == 0 {
+		return
+	}
+	e.redo = append(e.redo, e.snapshot())
+	e.restore(e.undo[len(e.undo)-1])
+	e.undo = e.undo[:len(e.undo)-1]
+}
+
+// redoStep redoes the last undone stroke, if any.
+func (e *mazeEditor) redoStep() {
+	if len(e.redo) == 0 {
+		return
+	}
+	e.undo = append(e.undo, e.snapshot())
+	e.restore(e.redo[len(e.redo)-1])
+	e.redo = e.redo[:len(e.redo)-1]
+}
+
+// check validates the board, showing the first problem as the status.
+func (e *mazeEditor) check() (*Maze, error) {
+	m := e.maze()
+	if err := m.Validate(); err != nil {
+		first, _, _ := strings.Cut(err.Error(), "\n")
+		e.status = strings.ToUpper(first)
+		return nil, err
+	}
+	return m, nil
+}
+
+// save validates the board and writes it to the editor's maze file.
+func (e *mazeEditor) save() error {
+	m, err := e.check()
+	if err != nil {
+		return err
+	}
+	if err := SaveMazeFile(e.path, m); err != nil {
+		e.status = strings.ToUpper(err.Error())
+		return err
+	}
+	e.status = tr(msgEditorSaved)
+	return nil
+}
+
+// SetMazeFile loads a maze file to play instead of the rules' maze, and
+// makes the editor open and save it.
+func (g *Game) SetMazeFile(path string) error {
+	m, err := LoadMazeFile(path)
+	if err != nil {
+		return err
+	}
+	g.board = m
+	g.editor.path = path
+	// The title screen and window are sized for the maze in play
+	g.setMaze(m.Clone())
+	return nil
+}
+
+// customBoard returns the board to play instead of the rules' maze: the
+// editor's during a test game, else the maze file's, or nil for neither.
+func (g *Game) customBoard() *Maze {
+	if g.editor.testing {
+		return g.editor.maze()
+	}
+	return g.board
+}
+
+// openEditor shows the editor, starting from the maze file's board or the
+// classic board the first time.
+func (g *Game) openEditor() {
+	if g.editor.layout == nil {
+		g.editor.load(cmp.Or(g.board, NewMaze()))
+		// The screen is laid out for the maze in play, so make it this board
+		g.setMaze(g.editor.maze())
+	}
+	if g.editor.path == "" {
+		g.editor.path = filepath.Join(g.captureDir, defaultMazeFile)
+	}
+	g.state = StateEditor
+}
+
+// testPlay starts a one-player game on the board being edited, if it is
+// playable.
+func (g *Game) testPlay() {
+	if _, err := g.editor.check(); err != nil {
+		return
+	}
+	g.editor.testing = true
+	g.editor.status = ""
+	g.startGame(time.Now().UnixNano(), 1)
+}
+
+// stopTest ends a test game and goes back to the editor.
+func (g *Game) stopTest() {
+	g.editor.testing = false
+	g.state = StateEditor
+}
+
+// updateEditor paints with the mouse and handles the editor's keys.
+func (g *Game) updateEditor() {
+	e := &g.editor
+	ctrl := ebiten.IsKeyPressed(ebiten.KeyControl) || ebiten.IsKeyPressed(ebiten.KeyMeta)
+	shift := ebiten.IsKeyPressed(ebiten.KeyShift)
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyEscape):
+		g.state = StateTitle
+		return
+	case ctrl && inpututil.IsKeyJustPressed(ebiten.KeyZ) && shift, ctrl && inpututil.IsKeyJustPressed(ebiten.KeyY):
+		e.redoStep()
+	case ctrl && inpututil.IsKeyJustPressed(ebiten.KeyZ):
+		e.undoStep()
+	case ctrl && inpututil.IsKeyJustPressed(ebiten.KeyS):
+		e.save()
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnter) && !ebiten.IsKeyPressed(ebiten.KeyAlt):
+		g.testPlay()
+		return
+	case inpututil.IsKeyJustPressed(ebiten.KeyX):
+		e.mirror = !e.mirror
+	case inpututil.IsKeyJustPressed(ebiten.KeyR):
+		e.turn = (e.turn + 1) % len(oneWayTurns)
+	case inpututil.IsKeyJustPressed(ebiten.KeyTab) && shift:
+		e.brush = wrapIndex(e.brush-1, brushCount)
+	case inpututil.IsKeyJustPressed(ebiten.KeyTab):
+		e.brush = wrapIndex(e.brush+1, brushCount)
+	}
+
+	// The arrow keys scroll boards larger than the view
+	const panSpeed = TileSize / 2
+	for _, d := range heldArrows(g.displayRotation()) {
+		dx, dy := nextTile(0, 0, d)
+		g.panCamera(float64(dx*panSpeed), float64(dy*panSpeed))
+	}
+
+	left, right := ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft), ebiten.IsMouseButtonPressed(ebiten.MouseButtonRight)
+	if !left && !right {
+		e.endStroke()
+		return
+	}
+	if x, y, ok := g.editorCursor(); ok {
+		e.paint(x, y, right && !left)
+	}
+}
+
+// editorCursor returns the board tile under the mouse, when it is over the
+// view.
+func (g *Game) editorCursor() (x, y int, ok bool) {
+	px, py, ok := g.cursorPosition()
+	if !ok || !image.Pt(int(math.Floor(px)), int(math.Floor(py))).In(g.viewRect()) {
+		return 0, 0, false
+	}
+	mx, my := g.toMaze(px, py)
+	x, y = int(math.Floor(mx/TileSize)), int(math.Floor(my/TileSize))
+	e := &g.editor
+	return x, y, y >= 0 && y < len(e.layout) && x >= 0 && x < len(e.layout[y])
+}
+
+// Editor colors for the ghost house floor, tunnels and the status panel.
+var (
+	editorHouse  = color.RGBA{0x20, 0x20, 0x40, 0xFF}
+	editorTunnel = color.RGBA{0x20, 0x40, 0x20, 0xFF}
+	editorPanel  = color.RGBA{0x00, 0x00, 0x00, 0xC0}
+)
+
+// drawEditor draws the board being edited with its spawns and scatter
+// targets, the tile under the mouse, and the brush, mirror and status above
+// the board with the keys below it.
+func (g *Game) drawEditor(screen Canvas) {
+	e := &g.editor
+	for y, row := range e.layout {
+		for x, ch := range row {
+			px, py := g.toScreen(float64(x*TileSize), float64(y*TileSize))
+			var tile *ebiten.Image
+			switch ch {
+			case '#':
+				fillRect(screen, px, py, TileSize, TileSize, theme.Palette.Wall)
+			case 'G':
+				fillRect(screen, px, py, TileSize, TileSize, editorHouse)
+			case '=':
+				fillRect(screen, px, py, TileSize, TileSize, editorTunnel)
+			case '-':
+				fillRect(screen, px, py+TileSize/2-1, TileSize, 2, theme.Palette.GhostDoor)
+			case '.':
+				tile = sprites.Dot
+			case 'o':
+				tile = sprites.PowerPellet
+			}
+			if tile != nil {
+				op := &ebiten.DrawImageOptions{}
+				op.GeoM.Translate(px, py)
+				screen.DrawImage(tile, op)
+			}
+			if mark, c, ok := tileMark(byte(ch)); ok {
+				for my := range TileSize {
+					for mx := range TileSize {
+						if mark[my][mx] {
+							fillRect(screen, px+float64(mx), py+float64(my), 1, 1, c)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	s := e.spawns
+	drawAt := func(img *ebiten.Image, p TilePos) {
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Translate(float64(p.X*TileSize+TileSize/2-6), float64(p.Y*TileSize+TileSize/2-6))
+		op.GeoM.Concat(g.viewGeoM())
+		screen.DrawImage(img, op)
+	}
+	drawAt(sprites.Fruits[0], s.Fruit)
+	drawAt(sprites.PacManFrames[1], s.PacMan)
+	for id := range s.Ghosts {
+		drawAt(sprites.GhostSprites[id][DirNone][0], s.Ghosts[id])
+		// Crosshair on the scatter target, as in the debug overlay
+		c := theme.Palette.Ghosts[id]
+		cx, cy := g.toScreen(float64(s.Scatter[id].X*TileSize+TileSize/2), float64(s.Scatter[id].Y*TileSize+TileSize/2))
+		fillRect(screen, cx-5, cy, 11, 1, c)
+		fillRect(screen, cx, cy-5, 1, 11, c)
+	}
+
+	if x, y, ok := g.editorCursor(); ok {
+		g.drawTileOutline(screen, x, y, theme.Palette.Highlight)
+		if e.mirror && e.brush < brushPacMan {
+			g.drawTileOutline(screen, len(e.layout[y])-1-x, y, theme.Palette.Highlight)
+		}
+	}
+	g.maskView(screen)
+
+	// Text goes in the HUD rows, which span the screen in the editor
+	w, _ := g.screenSize()
+	r := g.viewRect()
+	textTop := r.Min.Y - HUDTopRows*TileSize
+	brush := tr(brushNames[e.brush])
+	if e.brush == brushOneWay {
+		brush += " " + string(oneWayTurns[e.turn])
+	}
+	DrawText(screen, fmt.Sprintf(tr(msgEditorBrush), brush), 2, textTop+2, theme.Palette.Text)
+	mirror := tr(msgOff)
+	if e.mirror {
+		mirror = tr(msgOn)
+	}
+	DrawTextWith(screen, fmt.Sprintf(tr(msgEditorMirror), mirror), w-2, textTop+2, &TextOptions{Color: theme.Palette.Text, Align: AlignRight})
+	switch {
+	case e.status != "":
+		lines := wrapWords(e.status, (w-4)/(fontWidth+fontGap))
+		h := len(lines)*(fontHeight+fontLineGap) + 2
+		fillRect(screen, 0, float64(textTop+11), float64(w), float64(h), editorPanel)
+		DrawText(screen, strings.Join(lines, "\n"), 2, textTop+12, theme.Palette.Highlight)
+	case e.brush >= brushBlinky:
+		DrawText(screen, tr(msgEditorTarget), 2, textTop+12, theme.Palette.Text)
+	}
+	DrawTextWith(screen, tr(msgEditorHelp), w/2, r.Max.Y+1, &TextOptions{Color: theme.Palette.Text, Align: AlignCenter})
+}
+
+// drawTileOutline draws a one-pixel frame around tile x, y of the board.
+func (g *Game) drawTileOutline(screen Canvas, x, y int, c color.Color) {
+	px, py := g.toScreen(float64(x*TileSize), float64(y*TileSize))
+	fillRect(screen, px, py, TileSize, 1, c)
+	fillRect(screen, px, py+TileSize-1, TileSize, 1, c)
+	fillRect(screen, px, py, 1, TileSize, c)
+	fillRect(screen, px+TileSize-1, py, 1, TileSize, c)
+}
+
+// wrapWords breaks text into lines of at most width characters, between
+// words where it can.
+func wrapWords(text string, width int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		switch {
+		case line == "":
+			line = word
+		case len([]rune(line))+1+len([]rune(word)) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	return append(lines, line)
+}
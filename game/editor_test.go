@@ -0,0 +1,202 @@
+package game
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// openTestEditor opens the editor on the classic maze, saving to a
+// temporary directory.
+func openTestEditor(t *testing.T) *Game {
+	t.Helper()
+	g := New()
+	g.SetCaptureDir(t.TempDir())
+	g.openEditor()
+	return g
+}
+
+func TestEditorPaintMirrored(t *testing.T) {
+	g := openTestEditor(t)
+	e := &g.editor
+	e.brush = brushWall
+	e.mirror = true
+	if !e.paint(1, 1, false) {
+		t.Fatal("painting a wall over a dot should change the board")
+	}
+	m := e.maze()
+	if m.TileAt(1, 1) != TileWall || m.TileAt(MazeCols-2, 1) != TileWall {
+		t.Error("the wall should be painted on both halves")
+	}
+	if e.paint(1, 1, false) {
+		t.Error("painting the same tile again should change nothing")
+	}
+	e.paint(1, 1, true)
+	if m := e.maze(); m.TileAt(1, 1) != TileEmpty || m.TileAt(MazeCols-2, 1) != TileEmpty {
+		t.Error("right click should erase both halves")
+	}
+}
+
+func TestEditorPortalsAndOneWayDoors(t *testing.T) {
+	g := openTestEditor(t)
+	e := &g.editor
+	e.brush = brushPortal
+	for _, x := range []int{1, 5, 9} {
+		e.paint(x, 1, false)
+	}
+	if got := string(e.layout[1][:10]); got != "#0...0...1" {
+		t.Errorf("got row 1 %q, want a pair of portal 0 and then portal 1", got)
+	}
+	e.mirror = true
+	e.paint(1, 5, false)
+	if a, b := e.layout[5][1], e.layout[5][MazeCols-2]; a != '2' || b != '2' {
+		t.Errorf("got mirrored portals %c and %c, want a new pair 2", a, b)
+	}
+
+	e.brush = brushOneWay
+	e.turn = 0
+	e.paint(1, 8, false)
+	if a, b := e.layout[8][1], e.layout[8][MazeCols-2]; a != '>' || b != '<' {
+		t.Errorf("got one-way doors %c and %c, want > mirrored to <", a, b)
+	}
+}
+
+func TestEditorUndoRedo(t *testing.T) {
+	g := openTestEditor(t)
+	e := &g.editor
+	e.brush = brushWall
+	// One stroke across three tiles is one undo step
+	for x := 1; x <= 3; x++ {
+		e.paint(x, 5, false)
+	}
+	e.endStroke()
+	e.brush = brushPacMan
+	e.paint(1, 1, false)
+	e.endStroke()
+
+	e.undoStep()
+	if e.spawns.PacMan != classicSpawns.PacMan {
+		t.Errorf("got Pac-Man at %v after undo, want %v", e.spawns.PacMan, classicSpawns.PacMan)
+	}
+	e.undoStep()
+	if got := string(e.layout[5]); got != mazeLayout[5] {
+		t.Errorf("got row %q after undoing the stroke, want %q", got, mazeLayout[5])
+	}
+	e.undoStep() // nothing left
+	e.redoStep()
+	e.redoStep()
+	if got := e.maze().TileAt(3, 5); got != TileWall || e.spawns.PacMan != (TilePos{1, 1}) {
+		t.Error("redo should restore the stroke and Pac-Man's spawn")
+	}
+	e.undoStep()
+	e.paint(2, 2, false)
+	e.redoStep()
+	if e.spawns.PacMan != (TilePos{2, 2}) {
+		t.Error("a new change should clear the redo history")
+	}
+}
+
+func TestEditorGhostSpawnAndScatter(t *testing.T) {
+	g := openTestEditor(t)
+	e := &g.editor
+	e.brush = brushInky
+	e.paint(13, 13, false)
+	e.endStroke()
+	e.paint(5, 5, true)
+	if e.spawns.Ghosts[Inky] != (TilePos{13, 13}) || e.spawns.Scatter[Inky] != (TilePos{5, 5}) {
+		t.Errorf("got spawn %v and target %v", e.spawns.Ghosts[Inky], e.spawns.Scatter[Inky])
+	}
+	if len(e.undo) != 2 {
+		t.Errorf("got %d undo steps, want 2", len(e.undo))
+	}
+}
+
+func TestEditorSaveValidates(t *testing.T) {
+	g := openTestEditor(t)
+	e := &g.editor
+	e.brush = brushGhostDoor
+	e.paint(13, 12, true) // erase the door
+	e.paint(14, 12, true)
+	if err := e.save(); err == nil {
+		t.Fatal("a ghost house without a door should not save")
+	}
+	if _, err := os.Stat(e.path); !os.IsNotExist(err) {
+		t.Error("nothing should be written when validation fails")
+	}
+	if e.status == "" {
+		t.Error("the validation error should be shown")
+	}
+
+	e.undoStep()
+	if err := e.save(); err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(e.path) != defaultMazeFile {
+		t.Errorf("saved to %s", e.path)
+	}
+	m, err := LoadMazeFile(e.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.TotalDots() != NewMaze().TotalDots() {
+		t.Error("the saved maze should be the classic one again")
+	}
+}
+
+func TestEditorTestPlay(t *testing.T) {
+	g := openTestEditor(t)
+	e := &g.editor
+	e.brush = brushPacMan
+	e.paint(1, 5, false)
+	g.testPlay()
+	if g.state != StateReady || !e.testing {
+		t.Fatalf("got state %v, want a test game", g.state)
+	}
+	if g.maze.Spawns.PacMan != (TilePos{1, 5}) || g.pacman.TileX() != 1 || g.pacman.TileY() != 5 {
+		t.Error("the test game should play the edited board")
+	}
+
+	g.lives = 0
+	g.score = 5000
+	g.state = StateDeath
+	g.stateTimer = 1
+	g.updateDeath()
+	if g.state != StateGameOver {
+		t.Fatalf("got state %v, want game over", g.state)
+	}
+	if g.highScores.Best(ModeClassic) != 0 {
+		t.Error("test games should not enter high scores")
+	}
+	g.stateTimer = 1
+	g.updateGameOver()
+	if g.state != StateEditor || e.testing {
+		t.Errorf("got state %v, want the editor", g.state)
+	}
+
+	// The next game is on the classic board again
+	g.startGame(1, 1)
+	if g.maze.Spawns != classicSpawns {
+		t.Error("games after a test game should play the rules' maze")
+	}
+}
+
+func TestSetMazeFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "maze.txt")
+	m := NewMaze()
+	m.Spawns.PacMan = TilePos{1, 5}
+	if err := SaveMazeFile(path, m); err != nil {
+		t.Fatal(err)
+	}
+	g := New()
+	if err := g.SetMazeFile(path); err != nil {
+		t.Fatal(err)
+	}
+	g.startGame(1, 1)
+	if g.pacman.TileX() != 1 || g.pacman.TileY() != 5 {
+		t.Error("games should start on the maze file's board")
+	}
+	g.openEditor()
+	if g.editor.spawns.PacMan != (TilePos{1, 5}) || g.editor.path != path {
+		t.Error("the editor should open the maze file")
+	}
+}
@@ -0,0 +1,273 @@
+package game
+
+import (
+	"image/color"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// fontGlyphs defines a 5x7 pixel font covering printable ASCII, arrows and
+// the accented Latin letters used by the translations.
+// Each rune maps to 7 rows, where each uint8's lower 5 bits are the pixels.
+var fontGlyphs = map[rune][7]uint8{
+	' ':  {0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+	'!':  {0x04, 0x04, 0x04, 0x04, 0x04, 0x00, 0x04},
+	'"':  {0x0A, 0x0A, 0x0A, 0x00, 0x00, 0x00, 0x00},
+	'#':  {0x0A, 0x0A, 0x1F, 0x0A, 0x1F, 0x0A, 0x0A},
+	'$':  {0x04, 0x0F, 0x14, 0x0E, 0x05, 0x1E, 0x04},
+	'%':  {0x18, 0x19, 0x02, 0x04, 0x08, 0x13, 0x03},
+	'&':  {0x0C, 0x12, 0x14, 0x08, 0x15, 0x12, 0x0D},
+	'\'': {0x0C, 0x04, 0x08, 0x00, 0x00, 0x00, 0x00},
+	'(':  {0x02, 0x04, 0x08, 0x08, 0x08, 0x04, 0x02},
+	')':  {0x08, 0x04, 0x02, 0x02, 0x02, 0x04, 0x08},
+	'*':  {0x00, 0x04, 0x15, 0x0E, 0x15, 0x04, 0x00},
+	'+':  {0x00, 0x04, 0x04, 0x1F, 0x04, 0x04, 0x00},
+	',':  {0x00, 0x00, 0x00, 0x00, 0x0C, 0x04, 0x08},
+	'-':  {0x00, 0x00, 0x00, 0x1F, 0x00, 0x00, 0x00},
+	'.':  {0x00, 0x00, 0x00, 0x00, 0x00, 0x0C, 0x0C},
+	'/':  {0x00, 0x01, 0x02, 0x04, 0x08, 0x10, 0x00},
+	'0':  {0x0E, 0x11, 0x13, 0x15, 0x19, 0x11, 0x0E},
+	'1':  {0x04, 0x0C, 0x04, 0x04, 0x04, 0x04, 0x0E},
+	'2':  {0x0E, 0x11, 0x01, 0x06, 0x08, 0x10, 0x1F},
+	'3':  {0x0E, 0x11, 0x01, 0x06, 0x01, 0x11, 0x0E},
+	'4':  {0x02, 0x06, 0x0A, 0x12, 0x1F, 0x02, 0x02},
+	'5':  {0x1F, 0x10, 0x1E, 0x01, 0x01, 0x11, 0x0E},
+	'6':  {0x06, 0x08, 0x10, 0x1E, 0x11, 0x11, 0x0E},
+	'7':  {0x1F, 0x01, 0x02, 0x04, 0x08, 0x08, 0x08},
+	'8':  {0x0E, 0x11, 0x11, 0x0E, 0x11, 0x11, 0x0E},
+	'9':  {0x0E, 0x11, 0x11, 0x0F, 0x01, 0x02, 0x0C},
+	':':  {0x00, 0x0C, 0x0C, 0x00, 0x0C, 0x0C, 0x00},
+	';':  {0x00, 0x0C, 0x0C, 0x00, 0x0C, 0x04, 0x08},
+	'<':  {0x02, 0x04, 0x08, 0x10, 0x08, 0x04, 0x02},
+	'=':  {0x00, 0x00, 0x1F, 0x00, 0x1F, 0x00, 0x00},
+	'>':  {0x08, 0x04, 0x02, 0x01, 0x02, 0x04, 0x08},
+	'?':  {0x0E, 0x11, 0x01, 0x02, 0x04, 0x00, 0x04},
+	'@':  {0x0E, 0x11, 0x01, 0x0D, 0x15, 0x15, 0x0E},
+	'A':  {0x0E, 0x11, 0x11, 0x1F, 0x11, 0x11, 0x11},
+	'B':  {0x1E, 0x11, 0x11, 0x1E, 0x11, 0x11, 0x1E},
+	'C':  {0x0E, 0x11, 0x10, 0x10, 0x10, 0x11, 0x0E},
+	'D':  {0x1C, 0x12, 0x11, 0x11, 0x11, 0x12, 0x1C},
+	'E':  {0x1F, 0x10, 0x10, 0x1E, 0x10, 0x10, 0x1F},
+	'F':  {0x1F, 0x10, 0x10, 0x1E, 0x10, 0x10, 0x10},
+	'G':  {0x0E, 0x11, 0x10, 0x17, 0x11, 0x11, 0x0E},
+	'H':  {0x11, 0x11, 0x11, 0x1F, 0x11, 0x11, 0x11},
+	'I':  {0x0E, 0x04, 0x04, 0x04, 0x04, 0x04, 0x0E},
+	'J':  {0x07, 0x02, 0x02, 0x02, 0x02, 0x12, 0x0C},
+	'K':  {0x11, 0x12, 0x14, 0x18, 0x14, 0x12, 0x11},
+	'L':  {0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0x1F},
+	'M':  {0x11, 0x1B, 0x15, 0x15, 0x11, 0x11, 0x11},
+	'N':  {0x11, 0x19, 0x15, 0x13, 0x11, 0x11, 0x11},
+	'O':  {0x0E, 0x11, 0x11, 0x11, 0x11, 0x11, 0x0E},
+	'P':  {0x1E, 0x11, 0x11, 0x1E, 0x10, 0x10, 0x10},
+	'Q':  {0x0E, 0x11, 0x11, 0x11, 0x15, 0x12, 0x0D},
+	'R':  {0x1E, 0x11, 0x11, 0x1E, 0x14, 0x12, 0x11},
+	'S':  {0x0E, 0x11, 0x10, 0x0E, 0x01, 0x11, 0x0E},
+	'T':  {0x1F, 0x04, 0x04, 0x04, 0x04, 0x04, 0x04},
+	'U':  {0x11, 0x11, 0x11, 0x11, 0x11, 0x11, 0x0E},
+	'V':  {0x11, 0x11, 0x11, 0x11, 0x0A, 0x0A, 0x04},
+	'W':  {0x11, 0x11, 0x11, 0x15, 0x15, 0x1B, 0x11},
+	'X':  {0x11, 0x11, 0x0A, 0x04, 0x0A, 0x11, 0x11},
+	'Y':  {0x11, 0x11, 0x0A, 0x04, 0x04, 0x04, 0x04},
+	'Z':  {0x1F, 0x01, 0x02, 0x04, 0x08, 0x10, 0x1F},
+	'[':  {0x0E, 0x08, 0x08, 0x08, 0x08, 0x08, 0x0E},
+	'\\': {0x00, 0x10, 0x08, 0x04, 0x02, 0x01, 0x00},
+	']':  {0x0E, 0x02, 0x02, 0x02, 0x02, 0x02, 0x0E},
+	'^':  {0x04, 0x0A, 0x11, 0x00, 0x00, 0x00, 0x00},
+	'_':  {0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x1F},
+	'`':  {0x08, 0x04, 0x02, 0x00, 0x00, 0x00, 0x00},
+	'a':  {0x00, 0x00, 0x0E, 0x01, 0x0F, 0x11, 0x0F},
+	'b':  {0x10, 0x10, 0x16, 0x19, 0x11, 0x11, 0x1E},
+	'c':  {0x00, 0x00, 0x0E, 0x10, 0x10, 0x11, 0x0E},
+	'd':  {0x01, 0x01, 0x0D, 0x13, 0x11, 0x11, 0x0F},
+	'e':  {0x00, 0x00, 0x0E, 0x11, 0x1F, 0x10, 0x0E},
+	'f':  {0x06, 0x09, 0x08, 0x1C, 0x08, 0x08, 0x08},
+	'g':  {0x00, 0x0F, 0x11, 0x11, 0x0F, 0x01, 0x0E},
+	'h':  {0x10, 0x10, 0x16, 0x19, 0x11, 0x11, 0x11},
+	'i':  {0x04, 0x00, 0x0C, 0x04, 0x04, 0x04, 0x0E},
+	'j':  {0x02, 0x00, 0x06, 0x02, 0x02, 0x12, 0x0C},
+	'k':  {0x10, 0x10, 0x12, 0x14, 0x18, 0x14, 0x12},
+	'l':  {0x0C, 0x04, 0x04, 0x04, 0x04, 0x04, 0x0E},
+	'm':  {0x00, 0x00, 0x1A, 0x15, 0x15, 0x11, 0x11},
+	'n':  {0x00, 0x00, 0x16, 0x19, 0x11, 0x11, 0x11},
+	'o':  {0x00, 0x00, 0x0E, 0x11, 0x11, 0x11, 0x0E},
+	'p':  {0x00, 0x00, 0x1E, 0x11, 0x1E, 0x10, 0x10},
+	'q':  {0x00, 0x00, 0x0D, 0x13, 0x0F, 0x01, 0x01},
+	'r':  {0x00, 0x00, 0x16, 0x19, 0x10, 0x10, 0x10},
+	's':  {0x00, 0x00, 0x0E, 0x10, 0x0E, 0x01, 0x1E},
+	't':  {0x08, 0x08, 0x1C, 0x08, 0x08, 0x09, 0x06},
+	'u':  {0x00, 0x00, 0x11, 0x11, 0x11, 0x13, 0x0D},
+	'v':  {0x00, 0x00, 0x11, 0x11, 0x11, 0x0A, 0x04},
+	'w':  {0x00, 0x00, 0x11, 0x11, 0x15, 0x15, 0x0A},
+	'x':  {0x00, 0x00, 0x11, 0x0A, 0x04, 0x0A, 0x11},
+	'y':  {0x00, 0x00, 0x11, 0x11, 0x0F, 0x01, 0x0E},
+	'z':  {0x00, 0x00, 0x1F, 0x02, 0x04, 0x08, 0x1F},
+	'{':  {0x02, 0x04, 0x04, 0x08, 0x04, 0x04, 0x02},
+	'|':  {0x04, 0x04, 0x04, 0x04, 0x04, 0x04, 0x04},
+	'}':  {0x08, 0x04, 0x04, 0x02, 0x04, 0x04, 0x08},
+	'~':  {0x00, 0x00, 0x08, 0x15, 0x02, 0x00, 0x00},
+	'←':  {0x00, 0x04, 0x08, 0x1F, 0x08, 0x04, 0x00},
+	'→':  {0x00, 0x04, 0x02, 0x1F, 0x02, 0x04, 0x00},
+	'↑':  {0x04, 0x0E, 0x15, 0x04, 0x04, 0x04, 0x04},
+	'↓':  {0x04, 0x04, 0x04, 0x04, 0x15, 0x0E, 0x04},
+
+	// Accented Latin letters for translated text (Latin-1 subset)
+	'À': {0x08, 0x04, 0x0E, 0x11, 0x1F, 0x11, 0x11},
+	'Á': {0x02, 0x04, 0x0E, 0x11, 0x1F, 0x11, 0x11},
+	'Â': {0x04, 0x0A, 0x0E, 0x11, 0x1F, 0x11, 0x11},
+	'Ä': {0x0A, 0x00, 0x0E, 0x11, 0x1F, 0x11, 0x11},
+	'Å': {0x04, 0x0A, 0x04, 0x0E, 0x11, 0x1F, 0x11},
+	'Æ': {0x0F, 0x14, 0x14, 0x1F, 0x14, 0x14, 0x17},
+	'Ç': {0x0E, 0x11, 0x10, 0x10, 0x11, 0x0E, 0x04},
+	'È': {0x08, 0x04, 0x1F, 0x10, 0x1E, 0x10, 0x1F},
+	'É': {0x02, 0x04, 0x1F, 0x10, 0x1E, 0x10, 0x1F},
+	'Ê': {0x04, 0x0A, 0x1F, 0x10, 0x1E, 0x10, 0x1F},
+	'Ë': {0x0A, 0x00, 0x1F, 0x10, 0x1E, 0x10, 0x1F},
+	'Ì': {0x08, 0x04, 0x0E, 0x04, 0x04, 0x04, 0x0E},
+	'Í': {0x02, 0x04, 0x0E, 0x04, 0x04, 0x04, 0x0E},
+	'Î': {0x04, 0x0A, 0x0E, 0x04, 0x04, 0x04, 0x0E},
+	'Ï': {0x0A, 0x00, 0x0E, 0x04, 0x04, 0x04, 0x0E},
+	'Ñ': {0x0D, 0x16, 0x00, 0x19, 0x15, 0x13, 0x11},
+	'Ò': {0x08, 0x04, 0x0E, 0x11, 0x11, 0x11, 0x0E},
+	'Ó': {0x02, 0x04, 0x0E, 0x11, 0x11, 0x11, 0x0E},
+	'Ô': {0x04, 0x0A, 0x0E, 0x11, 0x11, 0x11, 0x0E},
+	'Ö': {0x0A, 0x00, 0x0E, 0x11, 0x11, 0x11, 0x0E},
+	'Ø': {0x0E, 0x13, 0x13, 0x15, 0x19, 0x19, 0x0E},
+	'Ù': {0x08, 0x04, 0x11, 0x11, 0x11, 0x11, 0x0E},
+	'Ú': {0x02, 0x04, 0x11, 0x11, 0x11, 0x11, 0x0E},
+	'Û': {0x04, 0x0A, 0x11, 0x11, 0x11, 0x11, 0x0E},
+	'Ü': {0x0A, 0x00, 0x11, 0x11, 0x11, 0x11, 0x0E},
+	'ß': {0x0C, 0x12, 0x12, 0x14, 0x12, 0x11, 0x16},
+	'à': {0x08, 0x04, 0x0E, 0x01, 0x0F, 0x11, 0x0F},
+	'á': {0x02, 0x04, 0x0E, 0x01, 0x0F, 0x11, 0x0F},
+	'â': {0x04, 0x0A, 0x0E, 0x01, 0x0F, 0x11, 0x0F},
+	'ä': {0x0A, 0x00, 0x0E, 0x01, 0x0F, 0x11, 0x0F},
+	'å': {0x04, 0x0A, 0x0E, 0x01, 0x0F, 0x11, 0x0F},
+	'æ': {0x00, 0x00, 0x1A, 0x05, 0x1F, 0x14, 0x0F},
+	'ç': {0x00, 0x0E, 0x10, 0x10, 0x11, 0x0E, 0x04},
+	'è': {0x08, 0x04, 0x0E, 0x11, 0x1F, 0x10, 0x0E},
+	'é': {0x02, 0x04, 0x0E, 0x11, 0x1F, 0x10, 0x0E},
+	'ê': {0x04, 0x0A, 0x0E, 0x11, 0x1F, 0x10, 0x0E},
+	'ë': {0x0A, 0x00, 0x0E, 0x11, 0x1F, 0x10, 0x0E},
+	'ì': {0x08, 0x04, 0x00, 0x0C, 0x04, 0x04, 0x0E},
+	'í': {0x02, 0x04, 0x00, 0x0C, 0x04, 0x04, 0x0E},
+	'î': {0x04, 0x0A, 0x00, 0x0C, 0x04, 0x04, 0x0E},
+	'ï': {0x0A, 0x00, 0x0C, 0x04, 0x04, 0x04, 0x0E},
+	'ñ': {0x0D, 0x16, 0x00, 0x16, 0x19, 0x11, 0x11},
+	'ò': {0x08, 0x04, 0x00, 0x0E, 0x11, 0x11, 0x0E},
+	'ó': {0x02, 0x04, 0x00, 0x0E, 0x11, 0x11, 0x0E},
+	'ô': {0x04, 0x0A, 0x00, 0x0E, 0x11, 0x11, 0x0E},
+	'ö': {0x0A, 0x00, 0x0E, 0x11, 0x11, 0x11, 0x0E},
+	'ø': {0x00, 0x00, 0x0F, 0x13, 0x15, 0x19, 0x1E},
+	'ù': {0x08, 0x04, 0x00, 0x11, 0x11, 0x13, 0x0D},
+	'ú': {0x02, 0x04, 0x00, 0x11, 0x11, 0x13, 0x0D},
+	'û': {0x04, 0x0A, 0x00, 0x11, 0x11, 0x13, 0x0D},
+	'ü': {0x0A, 0x00, 0x11, 0x11, 0x11, 0x13, 0x0D},
+}
+
+// fontMissingGlyph is drawn for runes without a glyph, so gaps are visible.
+var fontMissingGlyph = [7]uint8{0x1F, 0x11, 0x11, 0x11, 0x11, 0x11, 0x1F}
+
+const (
+	fontWidth   = 5
+	fontHeight  = 7
+	fontGap     = 1 // 1 pixel gap between characters
+	fontLineGap = 2 // pixels between lines of multiline text, before scaling
+)
+
+// Align is the horizontal alignment of text relative to its x coordinate.
+type Align int
+
+const (
+	AlignLeft   Align = iota // text starts at x
+	AlignCenter              // text is centered on x
+	AlignRight               // text ends at x
+)
+
+// TextOptions controls how DrawTextWith lays out text.
+type TextOptions struct {
+	Color color.Color // nil draws white
+	Align Align
+	Scale int // integer pixel scale; 0 is treated as 1
+}
+
+// glyphImages caches one white image per rune, tinted when drawn.
+var glyphImages = map[rune]*ebiten.Image{}
+
+// glyphImage returns the cached image for ch, generating it on first use.
+func glyphImage(ch rune) *ebiten.Image {
+	if img, ok := glyphImages[ch]; ok {
+		return img
+	}
+	glyph, ok := fontGlyphs[ch]
+	if !ok {
+		glyph = fontMissingGlyph
+	}
+	rgba := newRGBA(fontWidth, fontHeight)
+	white := color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF}
+	for row := 0; row < fontHeight; row++ {
+		for col := 0; col < fontWidth; col++ {
+			if glyph[row]&(1<<(fontWidth-1-col)) != 0 {
+				rgba.Set(col, row, white)
+			}
+		}
+	}
+	img := newImage(rgba)
+	glyphImages[ch] = img
+	return img
+}
+
+// lineWidth returns the unscaled pixel width of a single line of text.
+func lineWidth(line string) int {
+	n := len([]rune(line))
+	if n == 0 {
+		return 0
+	}
+	return n*(fontWidth+fontGap) - fontGap
+}
+
+// MeasureText returns the width and height in pixels of text drawn at the given
+// scale. Lines are separated by '\n'; the width is that of the longest line.
+func MeasureText(text string, scale int) (int, int) {
+	scale = max(scale, 1)
+	lines := strings.Split(text, "\n")
+	w := 0
+	for _, line := range lines {
+		w = max(w, lineWidth(line))
+	}
+	h := len(lines)*(fontHeight+fontLineGap) - fontLineGap
+	return w * scale, h * scale
+}
+
+// DrawText renders left-aligned text on screen using the pixel font.
+func DrawText(screen Canvas, text string, x, y int, c color.Color) {
+	DrawTextWith(screen, text, x, y, &TextOptions{Color: c})
+}
+
+// DrawTextWith renders text with the given alignment and scale. (x, y) is the
+// top of the first line; each line of multiline text is aligned on its own.
+func DrawTextWith(screen Canvas, text string, x, y int, opts *TextOptions) {
+	scale := max(opts.Scale, 1)
+	for i, line := range strings.Split(text, "\n") {
+		curX := x
+		switch opts.Align {
+		case AlignCenter:
+			curX -= lineWidth(line) * scale / 2
+		case AlignRight:
+			curX -= lineWidth(line) * scale
+		}
+		curY := y + i*(fontHeight+fontLineGap)*scale
+		for _, ch := range line {
+			if ch != ' ' {
+				op := &ebiten.DrawImageOptions{}
+				op.GeoM.Scale(float64(scale), float64(scale))
+				op.GeoM.Translate(float64(curX), float64(curY))
+				if opts.Color != nil {
+					op.ColorScale.ScaleWithColor(opts.Color)
+				}
+				screen.DrawImage(glyphImage(ch), op)
+			}
+			curX += (fontWidth + fontGap) * scale
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package game
+
+import "testing"
+
+func TestFontCoversPrintableASCII(t *testing.T) {
+	for ch := rune(0x20); ch < 0x7F; ch++ {
+		if _, ok := fontGlyphs[ch]; !ok {
+			t.Errorf("missing glyph for %q", ch)
+		}
+	}
+	for _, ch := range "←↑→↓" {
+		if _, ok := fontGlyphs[ch]; !ok {
+			t.Errorf("missing glyph for %q", ch)
+		}
+	}
+}
+
+func TestMeasureText(t *testing.T) {
+	tests := []struct {
+		text  string
+		scale int
+		w, h  int
+	}{
+		{"", 1, 0, 7},
+		{"A", 1, 5, 7},
+		{"GAME OVER", 1, 53, 7},
+		{"GAME OVER", 2, 106, 14},
+		{"PRESS SPACE\nTO START", 1, 65, 16},
+	}
+	for _, tt := range tests {
+		w, h := MeasureText(tt.text, tt.scale)
+		if w != tt.w || h != tt.h {
+			t.Errorf("MeasureText(%q, %d) = %d, %d; want %d, %d", tt.text, tt.scale, w, h, tt.w, tt.h)
+		}
+	}
+}
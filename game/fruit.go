@@ -0,0 +1,277 @@
+package game
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Fruit is the bonus item that appears twice a level, below the ghost house
+// in the classic maze.
+type Fruit int
+
+const (
+	FruitNone Fruit = iota
+	FruitCherries
+	FruitStrawberry
+	FruitOrange
+	FruitApple
+	FruitMelon
+	FruitGalaxian
+	FruitBell
+	FruitKey
+)
+
+// fruitNames are the fruits' names in rules files and sprite sheet labels,
+// indexed by Fruit.
+var fruitNames = []string{"none", "cherries", "strawberry", "orange", "apple", "melon", "galaxian", "bell", "key"}
+
+// fruitPoints are the points for eating each fruit, indexed by Fruit.
+var fruitPoints = []int{0, 100, 300, 500, 700, 1000, 2000, 3000, 5000}
+
+// String returns the fruit's name.
+func (f Fruit) String() string {
+	if f < 0 || int(f) >= len(fruitNames) {
+		return "unknown"
+	}
+	return fruitNames[f]
+}
+
+// Points returns the score for eating the fruit.
+func (f Fruit) Points() int {
+	if f < 0 || int(f) >= len(fruitPoints) {
+		return 0
+	}
+	return fruitPoints[f]
+}
+
+// MarshalText writes the fruit as its name, as in rules files.
+func (f Fruit) MarshalText() ([]byte, error) {
+	return []byte(f.String()), nil
+}
+
+// UnmarshalText reads a fruit from its name.
+func (f *Fruit) UnmarshalText(text []byte) error {
+	for i, name := range fruitNames {
+		if name == string(text) {
+			*f = Fruit(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown fruit %q", text)
+}
+
+// The fruit appears when 70 and 170 of the arcade maze's 244 dots have been
+// eaten, at the same shares of other mazes, and stays for fruitTicks. Its
+// points show where it was for fruitScoreTicks after it is eaten.
+const (
+	arcadeMazeDots  = 244
+	fruitTicks      = 570 // 9.5 seconds
+	fruitScoreTicks = 120
+)
+
+var fruitDots = []int{70, 170}
+
+// fruitArt is a 12x12 picture of each fruit after FruitNone. Letters pick a
+// color from fruitColors; dots are transparent. The arcade's fruit keep their
+// colors in every theme.
+var fruitArt = [][]string{
+	{ // cherries
+		".........kk.",
+		".......kk.k.",
+		"......k...k.",
+		".....k...k..",
+		"..rrk...k...",
+		".rrrrr.rrr..",
+		"rrrrrrrrrrr.",
+		"rwrrrrrrrrrr",
+		"rrwrrrrwrrrr",
+		"rrrrrrrrwrrr",
+		".rrrr.rrrrr.",
+		"......rrr...",
+	},
+	{ // strawberry
+		"....gggg....",
+		"..gggggggg..",
+		".rrrrggrrrr.",
+		"rrwrrrrrrwrr",
+		"rrrrrwrrrrrr",
+		"rwrrrrrrwrrr",
+		"rrrrwrrrrrwr",
+		".rrrrrrwrrr.",
+		".rwrrrrrrrr.",
+		"..rrrrwrrr..",
+		"...rrrrrr...",
+		".....rr.....",
+	},
+	{ // orange
+		".....g......",
+		"....gggg....",
+		"..oogggoo...",
+		".oooooooooo.",
+		"oooooooooooo",
+		"oowooooooooo",
+		"owoooooooooo",
+		"oooooooooooo",
+		"oooooooooooo",
+		".oooooooooo.",
+		"..oooooooo..",
+		"....oooo....",
+	},
+	{ // apple
+		".....k......",
+		"..rrrkkrrr..",
+		".rrrrrkrrrr.",
+		"rrrrrrrrrrrr",
+		"rwrrrrrrrrrr",
+		"rwrrrrrrrrrr",
+		"rrrrrrrrrrrr",
+		"rrrrrrrrrrrr",
+		".rrrrrrrrrr.",
+		".rrrrrrrrrr.",
+		"..rrr..rrr..",
+		"............",
+	},
+	{ // melon
+		".....k......",
+		"....kk......",
+		"..gggggggg..",
+		".gGgggGgggg.",
+		"gGgggGgggGgg",
+		"ggggGgggGggg",
+		"gggGgggGgggg",
+		"ggGgggGgggGg",
+		"gGgggGgggGgg",
+		".ggggGgggGg.",
+		"..gggggggg..",
+		"............",
+	},
+	{ // galaxian
+		".....y......",
+		"....yyy.....",
+		"b...yyy...b.",
+		"b..yyyyy..b.",
+		"bb.yrrry.bb.",
+		"bbbrrrrrbbb.",
+		".bbrrrrrbb..",
+		"..b.rrr.b...",
+		"....r.r.....",
+		"....r.r.....",
+		"............",
+		"............",
+	},
+	{ // bell
+		"....yyyy....",
+		"...yyyyyy...",
+		"..yyyyyyyy..",
+		"..ywyyyyyy..",
+		".yywyyyyyyy.",
+		".ywyyyyyyyy.",
+		".yyyyyyyyyy.",
+		"yyyyyyyyyyyy",
+		"yyyyyyyyyyyy",
+		"....cwwc....",
+		".....cc.....",
+		"............",
+	},
+	{ // key
+		"...cccccc...",
+		"..cc.cc.cc..",
+		"..cccccccc..",
+		"...cccccc...",
+		".....ww.....",
+		".....www....",
+		".....ww.....",
+		".....www....",
+		".....ww.....",
+		".....www....",
+		".....ww.....",
+		"............",
+	},
+}
+
+var fruitColors = map[byte]color.RGBA{
+	'r': {0xFF, 0x00, 0x00, 0xFF}, // red
+	'w': {0xFF, 0xFF, 0xFF, 0xFF}, // white highlights
+	'k': {0xDE, 0x97, 0x51, 0xFF}, // brown stalks
+	'g': {0x00, 0xB0, 0x00, 0xFF}, // green
+	'G': {0x90, 0xFF, 0x90, 0xFF}, // light green melon stripes
+	'o': {0xFF, 0xB8, 0x52, 0xFF}, // orange
+	'y': {0xFF, 0xFF, 0x00, 0xFF}, // yellow
+	'b': {0x21, 0x21, 0xDE, 0xFF}, // blue
+	'c': {0x00, 0xDE, 0xFF, 0xFF}, // cyan
+}
+
+// FruitSpriteSize is the width/height of fruit sprites in pixels.
+const FruitSpriteSize = 13
+
+// GenerateFruitSprite generates a 13x13 sprite of a fruit other than
+// FruitNone.
+func GenerateFruitSprite(f Fruit) *ebiten.Image {
+	img := newRGBA(FruitSpriteSize, FruitSpriteSize)
+	for y, row := range fruitArt[f-1] {
+		for x := 0; x < len(row); x++ {
+			if c, ok := fruitColors[row[x]]; ok {
+				img.Set(x, y, c)
+			}
+		}
+	}
+	return newImage(img)
+}
+
+// spawnFruit puts the level's fruit out if the dot just eaten was one of the
+// dots that bring it.
+func (g *Game) spawnFruit() {
+	fruit := g.difficulty().Fruit
+	if fruit == FruitNone {
+		return
+	}
+	eaten := g.maze.TotalDots() - g.maze.RemainingDots()
+	for _, n := range fruitDots {
+		if eaten == n*g.maze.TotalDots()/arcadeMazeDots {
+			g.fruit = fruit
+			g.fruitTimer = fruitTicks
+		}
+	}
+}
+
+// updateFruit counts down the fruit and its points, and lets Pac-Man eat the
+// fruit.
+func (g *Game) updateFruit() {
+	if g.fruitScoreTimer > 0 {
+		g.fruitScoreTimer--
+	}
+	if g.fruitTimer == 0 {
+		return
+	}
+	g.fruitTimer--
+	if spot := g.maze.Spawns.Fruit; g.pacman.TileX() == spot.X && g.pacman.TileY() == spot.Y {
+		g.fruitScore = g.fruit.Points()
+		g.score += g.fruitScore
+		g.fruitScoreTimer = fruitScoreTicks
+		g.fruitTimer = 0
+		g.sound.PlayFruit()
+	}
+}
+
+// clearFruit removes the fruit and its points, when Pac-Man dies or a level
+// starts.
+func (g *Game) clearFruit() {
+	g.fruitTimer = 0
+	g.fruitScoreTimer = 0
+}
+
+// drawFruit draws the fruit at its spawn, or the points it scored.
+func (g *Game) drawFruit(screen Canvas) {
+	spot := g.maze.Spawns.Fruit
+	x, y := g.toScreen(float64(spot.X*TileSize+TileSize/2), float64(spot.Y*TileSize+TileSize/2))
+	switch {
+	case g.fruitTimer > 0:
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Translate(x-6, y-6)
+		screen.DrawImage(sprites.Fruits[g.fruit-1], op)
+	case g.fruitScoreTimer > 0:
+		DrawTextWith(screen, fmt.Sprintf("%d", g.fruitScore), int(x), int(y)-fontHeight/2, &TextOptions{Color: theme.Palette.GhostDoor, Align: AlignCenter})
+	}
+}
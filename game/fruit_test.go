@@ -0,0 +1,80 @@
+package game
+
+import "testing"
+
+// eatDots eats dots in reading order, through checkDotConsumption, until n
+// have been eaten since the maze was full.
+func eatDots(g *Game, n int) {
+	for y := 0; y < g.maze.Height; y++ {
+		for x := 0; x < g.maze.Width; x++ {
+			if g.maze.TotalDots()-g.maze.RemainingDots() >= n {
+				return
+			}
+			if t := g.maze.TileAt(x, y); t == TileDot || t == TilePowerPellet {
+				g.pacman.X, g.pacman.Y = float64(x*TileSize+TileSize/2), float64(y*TileSize+TileSize/2)
+				g.checkDotConsumption()
+			}
+		}
+	}
+}
+
+func TestFruitAppears(t *testing.T) {
+	g := New()
+	g.startGame(1, 1)
+	eatDots(g, 69)
+	if g.fruitTimer != 0 {
+		t.Fatal("no fruit before the 70th dot")
+	}
+	eatDots(g, 70)
+	if g.fruitTimer != fruitTicks || g.fruit != FruitCherries {
+		t.Fatalf("after 70 dots: got %v for %d ticks, want cherries for %d", g.fruit, g.fruitTimer, fruitTicks)
+	}
+
+	for range fruitTicks {
+		g.updateFruit()
+	}
+	if g.fruitTimer != 0 {
+		t.Error("the fruit should go after fruitTicks")
+	}
+	eatDots(g, 170)
+	if g.fruitTimer != fruitTicks {
+		t.Error("a second fruit should come at 170 dots")
+	}
+}
+
+func TestFruitEaten(t *testing.T) {
+	sound := NewSoundRecorder(func() int { return 0 })
+	g := New()
+	g.SetSound(sound)
+	g.startGame(1, 1)
+	g.level = 5
+	g.fruit, g.fruitTimer = FruitApple, fruitTicks
+	g.pacman.X = float64(FruitX*TileSize + TileSize/2)
+	g.pacman.Y = float64(FruitY*TileSize + TileSize/2)
+	g.updateFruit()
+	if g.score != 700 || g.fruitTimer != 0 || g.fruitScoreTimer != fruitScoreTicks {
+		t.Errorf("got score %d, fruit timer %d, points timer %d", g.score, g.fruitTimer, g.fruitScoreTimer)
+	}
+	if sound.Count("fruit") != 1 {
+		t.Error("eating the fruit should play its sound")
+	}
+
+	loseLife(g)
+	if g.fruitScoreTimer != 0 {
+		t.Error("dying should clear the fruit's points")
+	}
+}
+
+func TestFruitText(t *testing.T) {
+	for f := FruitNone; f <= FruitKey; f++ {
+		text, _ := f.MarshalText()
+		var got Fruit
+		if err := got.UnmarshalText(text); err != nil || got != f {
+			t.Errorf("%v: round trip gave %v, %v", f, got, err)
+		}
+	}
+	var f Fruit
+	if err := f.UnmarshalText([]byte("banana")); err == nil {
+		t.Error("unknown fruit should be an error")
+	}
+}
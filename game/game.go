@@ -1,65 +1,148 @@
 package game
 
 import (
-	"image/color"
+	"fmt"
 	"math"
+	"math/rand"
+	"slices"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
 )
 
 const (
-	TileSize     = 8
-	MazeCols     = 28
-	MazeRows     = 31
-	HUDTopRows   = 3
-	HUDBotRows   = 2
-	ScreenWidth  = MazeCols * TileSize                              // 224
-	ScreenHeight = (MazeRows + HUDTopRows + HUDBotRows) * TileSize // 288
-	Scale        = 3
+	TileSize   = 8
+	MazeCols   = 28 // size of the classic maze; boards of any size can be loaded
+	MazeRows   = 31
+	HUDTopRows = 3 // rows of HUD above the maze
+	HUDBotRows = 2 // and below it
+
+	// MinScreenWidth and MinScreenHeight are the smallest game screen, the
+	// classic maze's, so the title and settings screens fit. Smaller mazes
+	// are centered on it.
+	MinScreenWidth  = MazeCols * TileSize                             // 224
+	MinScreenHeight = (MazeRows + HUDTopRows + HUDBotRows) * TileSize // 288
+
+	// FrightenedFlashTicks is how long each blue or white half of a
+	// frightened-mode flash lasts.
+	FrightenedFlashTicks = 14
 )
 
 type Game struct {
 	maze      *Maze
+	mazeImage *ebiten.Image // pre-rendered walls and ghost door
+	minimap   *ebiten.Image // pre-rendered minimap walls, a pixel per tile
+	camera    camera        // the part of the maze on screen
+	frame     *ebiten.Image // game screen, scaled up to the window by present
+	windowW   int           // window size in device pixels, from Layout
+	windowH   int
+	windowed  bool // ApplyWindowSettings has sized the window, so new maze sizes resize it
 	pacman    *PacMan
 	ghosts    [4]*Ghost
 	modeTimer *ModeTimer
 
-	sound *SoundManager
-
-	state      GameState
-	stateTimer int
-	tickCount  int
+	sound Sound
+
+	state          GameState
+	stateTimer     int
+	tickCount      int
+	settingsCursor int // selected row on the settings screen
+	settings       Settings
+	settingsPath   string   // where settings are saved, empty to not save
+	savedSettings  Settings // the settings in the file at settingsPath
+	runSettings    Settings // the settings when last saved, or given for this run
+	rules          Rules
+	board          *Maze // custom board from a maze file, nil for the rules' maze
+	editor         mazeEditor
+	table          DifficultyTable // rules.Table(), per level
+
+	modeID         string     // mode of the next or current game, one of Modes
+	mode           gameMode   // rules of the current game's mode
+	highScores     HighScores // each mode's best scores
+	highScoresPath string     // where high scores are saved, empty to not save
 
 	score            int
-	highScore        int
 	lives            int
 	level            int
-	ghostsEatenCombo int  // resets each power pellet
-	frightenedTimer  int  // ticks remaining for frightened mode
-	extraLifeAwarded bool // true after 10,000 point bonus life
+	ghostsEatenCombo int // resets each power pellet
+	frightenedTimer  int // ticks remaining for frightened mode
+	flashCount       int // flashes shown before frightened mode ends
+	nextExtraLife    int // score for the next extra life, 0 when none are left
+
+	fruit           Fruit // bonus fruit showing while fruitTimer runs
+	fruitTimer      int   // ticks until the fruit goes, 0 when there is none
+	fruitScore      int   // points of the fruit just eaten
+	fruitScoreTimer int   // ticks to show fruitScore for
+
+	players int    // 1 or 2 in the current game
+	current int    // whose turn it is: 0 for player one, 1 for player two
+	waiting player // progress of the other player in a two-player game
+
+	replay      *Replay // the current game's seed and inputs
+	replayInput int     // next input to play back from replay
+	playTicks   int     // ticks of play since the game started
+	playback    bool    // replay drives the game instead of the keyboard
+
+	captureDir string       // where screenshots, recordings and replays go
+	gifFPS     int          // frame rate of GIF recordings
+	recorder   *GIFRecorder // nil unless recording
+
+	debugOverlay bool // draw AI targets, tile grid and timers over the maze
+	console      debugConsole
 }
 
 func New() *Game {
 	InitSprites()
-	sm := NewSoundManager()
+	maze := NewMaze()
+	rules := DefaultRules()
 	return &Game{
-		sound:     sm,
-		maze:      NewMaze(),
-		pacman:    NewPacMan(),
-		ghosts:    NewGhosts(),
-		modeTimer: NewModeTimer(1),
-		state:     StateTitle,
-		lives:     3,
-		level:     1,
+		sound:      NoSound{},
+		maze:       maze,
+		mazeImage:  RenderMazeBackground(maze),
+		minimap:    renderMinimap(maze),
+		pacman:     NewPacMan(),
+		ghosts:     NewGhosts(),
+		modeTimer:  NewModeTimer(rules.DifficultyAt(1).Schedule),
+		state:      StateTitle,
+		settings:   DefaultSettings(),
+		rules:      rules,
+		table:      rules.Table(),
+		modeID:     ModeClassic,
+		mode:       classicMode{},
+		highScores: HighScores{},
+		gifFPS:     defaultGIFFPS,
+		lives:      rules.StartingLives,
+		level:      1,
 	}
 }
 
 func (g *Game) Update() error {
+	if g.updateConsole() {
+		return nil
+	}
 	g.tickCount++
 
+	if !g.playback {
+		if inpututil.IsKeyJustPressed(ebiten.KeyM) {
+			g.toggleMute()
+		}
+		g.updateFullscreenKey()
+		g.updateDebugOverlayKey()
+		g.updateCapture()
+		if g.editor.testing && g.inGame() && inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+			g.stopTest()
+			return nil
+		}
+	}
+
 	switch g.state {
 	case StateTitle:
 		g.updateTitle()
+	case StateSettings:
+		g.updateSettings()
+	case StateEditor:
+		g.updateEditor()
 	case StateReady:
 		g.updateReady()
 	case StatePlaying:
@@ -71,33 +154,119 @@ func (g *Game) Update() error {
 	case StateGameOver:
 		g.updateGameOver()
 	}
+	if g.inGame() {
+		g.updateCamera()
+	}
+	g.sound.SetLoop(g.backgroundLoop(), sirenStage(g.maze.RemainingDots(), g.maze.TotalDots()))
 	return nil
 }
 
+// backgroundLoop picks the looping sound for the current moment of play:
+// returning eyes take priority over frightened mode, which replaces the siren.
+func (g *Game) backgroundLoop() Loop {
+	if g.state != StatePlaying {
+		return LoopNone
+	}
+	for _, ghost := range g.ghosts {
+		if ghost.Mode == GhostEaten {
+			return LoopEyes
+		}
+	}
+	if g.frightenedTimer > 0 {
+		return LoopFrightened
+	}
+	return LoopSiren
+}
+
+// updateTitle picks a mode with up/down and starts it. Two-player games are
+// classic only.
 func (g *Game) updateTitle() {
-	if ebiten.IsKeyPressed(ebiten.KeySpace) {
-		g.score = 0
-		g.lives = 3
-		g.level = 1
-		g.maze.Reset()
-		g.pacman = NewPacMan()
-		g.ghosts = NewGhosts()
-		g.modeTimer = NewModeTimer(1)
-		g.frightenedTimer = 0
-		g.extraLifeAwarded = false
-		g.applyDifficulty()
-		g.state = StateReady
-		g.stateTimer = 120 // 2 seconds
+	if inpututil.IsKeyJustPressed(ebiten.KeyS) {
+		g.settingsCursor = 0
+		g.state = StateSettings
+		return
 	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyE) {
+		g.openEditor()
+		return
+	}
+	cursor := slices.Index(Modes, g.modeID)
+	arrow := justPressedArrow(g.displayRotation())
+	switch {
+	case arrow == DirUp:
+		g.modeID = Modes[wrapIndex(cursor-1, len(Modes))]
+	case arrow == DirDown:
+		g.modeID = Modes[wrapIndex(cursor+1, len(Modes))]
+	case ebiten.IsKeyPressed(ebiten.KeySpace) || ebiten.IsKeyPressed(ebiten.Key1) ||
+		inpututil.IsKeyJustPressed(ebiten.KeyEnter) && !ebiten.IsKeyPressed(ebiten.KeyAlt): // Alt+Enter is fullscreen
+		g.startGame(time.Now().UnixNano(), 1)
+	case ebiten.IsKeyPressed(ebiten.Key2) && g.modeID == ModeClassic:
+		g.startGame(time.Now().UnixNano(), 2)
+	}
+}
+
+// startGame starts a new game of the chosen mode for one or two players
+// whose ghosts make random choices from seed, and begins recording its
+// replay.
+func (g *Game) startGame(seed int64, players int) {
+	seedRNG(seed)
+	g.replay = &Replay{Seed: seed, SpeedPercent: g.settings.SpeedPercent, Players: players, Mode: g.modeID, Difficulty: g.rules.Difficulty, Maze: g.rules.Maze, Board: g.boardHash(), Rules: g.rules.Hash()}
+	g.replayInput = 0
+	g.playTicks = 0
+	g.players = players
+	g.current = 0
+	g.score = 0
+	g.lives = g.rules.StartingLives
+	g.level = 1
+	g.resetMaze()
+	g.waiting = player{maze: g.maze.Clone(), lives: g.rules.StartingLives, level: 1, nextExtraLife: g.rules.ExtraLifeScore}
+	g.spawnActors()
+	g.modeTimer = NewModeTimer(g.difficulty().Schedule)
+	g.frightenedTimer = 0
+	g.clearFruit()
+	g.nextExtraLife = g.rules.ExtraLifeScore
+	g.mode = newMode(g.modeID)
+	g.mode.start(g)
+	g.applyDifficulty()
+	g.state = StateReady
+	g.stateTimer = g.rules.ReadyTicks
+	g.sound.PlayIntro()
 }
 
-// applyDifficulty sets speeds and timers based on current level.
+// applyDifficulty sets Pac-Man's and the ghosts' speeds for the current
+// level and what each is doing, and the mode's ghost speed-up, scaled by the
+// game speed setting.
 func (g *Game) applyDifficulty() {
-	d := GetDifficulty(g.level)
-	g.pacman.Speed = d.PacManSpeed
+	d := g.difficulty()
+	scale := g.settings.speedScale()
+	g.pacman.Speed = d.PacManSpeed * scale
+	if g.frightenedTimer > 0 {
+		g.pacman.Speed = d.PacManFrightenedSpeed * scale
+	}
 	for _, ghost := range g.ghosts {
-		ghost.Speed = d.GhostSpeed
+		ghost.Speed = min(ghostSpeed(d, ghost, g.maze)*g.mode.ghostSpeedScale(), maxSpeed) * scale
+	}
+}
+
+// ghostSpeed returns how fast a ghost moves: returning eyes fastest, then
+// slowed in tunnels or when frightened, and Blinky faster as the dots run
+// out.
+func ghostSpeed(d DifficultyParams, ghost *Ghost, m *Maze) float64 {
+	switch {
+	case ghost.Mode == GhostEaten:
+		return d.EatenSpeed
+	case ghost.InHouse:
+		return d.GhostSpeed
+	case m.IsTunnel(ghost.TileX(), ghost.TileY()):
+		return d.GhostTunnelSpeed
+	case ghost.Mode == GhostFrightened:
+		return d.FrightenedSpeed
+	case ghost.ID == Blinky && m.RemainingDots() <= d.Elroy2Dots:
+		return d.Elroy2Speed
+	case ghost.ID == Blinky && m.RemainingDots() <= d.Elroy1Dots:
+		return d.Elroy1Speed
 	}
+	return d.GhostSpeed
 }
 
 func (g *Game) updateReady() {
@@ -108,9 +277,11 @@ func (g *Game) updateReady() {
 }
 
 func (g *Game) updatePlaying() {
-	ReadInput(g.pacman)
+	g.readInput()
+	g.applyDifficulty()
 	g.pacman.Move(g.maze)
 	g.checkDotConsumption()
+	g.updateFruit()
 
 	// Update frightened timer
 	if g.frightenedTimer > 0 {
@@ -131,13 +302,19 @@ func (g *Game) updatePlaying() {
 		UpdateGhost(ghost, g.maze, g.pacman, globalMode)
 	}
 
+	// The mode's clock or score for time alive
+	if g.mode.tick(g) {
+		g.endGame()
+		return
+	}
+
 	// Check collisions
 	g.checkGhostCollisions()
 
-	// Check level clear
-	if g.maze.RemainingDots() == 0 {
+	// Check level clear, unless the mode refills the maze
+	if g.maze.RemainingDots() == 0 && g.mode.mazeCleared(g) {
 		g.state = StateLevelClear
-		g.stateTimer = 120 // 2 seconds of flashing
+		g.stateTimer = g.rules.LevelClearTicks
 		g.sound.PlayLevelClear()
 	}
 }
@@ -145,30 +322,32 @@ func (g *Game) updatePlaying() {
 func (g *Game) updateDeath() {
 	g.stateTimer--
 
-	// First 30 ticks: freeze (show last frame). Then 88 ticks: death animation (11 frames × 8 ticks each).
-	elapsed := 120 - g.stateTimer
-	if elapsed > 30 {
-		frame := (elapsed - 30) / 8
-		if frame > 10 {
-			frame = 10
-		}
-		g.pacman.DeathFrame = frame
+	// The first quarter of death_ticks is a freeze on the last frame; the
+	// death animation's frames share the rest evenly (30 and 90 ticks by
+	// default).
+	freeze := g.rules.DeathTicks / 4
+	elapsed := g.rules.DeathTicks - g.stateTimer
+	if elapsed > freeze {
+		frames := len(sprites.PacManDeath)
+		g.pacman.DeathFrame = min((elapsed-freeze)*frames/(g.rules.DeathTicks-freeze), frames-1)
 	}
 
 	if g.stateTimer <= 0 {
-		if g.lives <= 0 {
-			if g.score > g.highScore {
-				g.highScore = g.score
-			}
-			g.state = StateGameOver
-			g.stateTimer = 180 // 3 seconds
+		if g.lives <= 0 && (g.players < 2 || g.waiting.lives <= 0) {
+			g.endGame()
 		} else {
-			g.pacman = NewPacMan()
-			g.ghosts = NewGhosts()
-			g.modeTimer.Reset()
+			if g.players == 2 && g.waiting.lives > 0 {
+				g.switchPlayer()
+				g.modeTimer = NewModeTimer(g.difficulty().Schedule)
+			} else {
+				g.modeTimer.Reset()
+			}
+			g.spawnActors()
 			g.frightenedTimer = 0
+			g.clearFruit()
+			g.applyDifficulty()
 			g.state = StateReady
-			g.stateTimer = 120
+			g.stateTimer = g.rules.ReadyTicks
 		}
 	}
 }
@@ -177,20 +356,79 @@ func (g *Game) updateLevelClear() {
 	g.stateTimer--
 	if g.stateTimer <= 0 {
 		g.level++
-		g.maze.Reset()
-		g.pacman = NewPacMan()
-		g.ghosts = NewGhosts()
-		g.modeTimer = NewModeTimer(g.level)
-		g.frightenedTimer = 0
-		g.applyDifficulty()
-		g.state = StateReady
-		g.stateTimer = 120
+		g.startLevel()
+	}
+}
+
+// startLevel refills the maze and puts Pac-Man, the ghosts and the mode timer
+// back at the start of g.level, then shows READY!.
+func (g *Game) startLevel() {
+	g.resetMaze()
+	g.spawnActors()
+	g.modeTimer = NewModeTimer(g.difficulty().Schedule)
+	g.frightenedTimer = 0
+	g.clearFruit()
+	g.applyDifficulty()
+	g.state = StateReady
+	g.stateTimer = g.rules.ReadyTicks
+}
+
+// spawnActors puts Pac-Man and the ghosts at the maze's spawns, and the
+// camera on Pac-Man.
+func (g *Game) spawnActors() {
+	g.pacman = NewPacManAt(g.maze.Spawns.PacMan)
+	g.ghosts = NewGhostsAt(g.maze.Spawns)
+	g.snapCamera()
+}
+
+// resetMaze fills the maze for the current level: a custom board from a maze
+// file or the editor, the classic board, or with the generated maze rule a
+// new board, the same for every game played from the same seed.
+func (g *Game) resetMaze() {
+	switch board := g.customBoard(); {
+	case board != nil:
+		g.setMaze(board.Clone())
+	case g.rules.Maze == MazeGenerated:
+		r := rand.New(rand.NewSource(g.replay.Seed*mazeSeedStride + int64(g.level)))
+		g.setMaze(NewMazeFromLayout(GenerateMaze(r)))
+	default:
+		g.setMaze(NewMaze())
+	}
+}
+
+// mazeSeedStride spreads games' seeds apart so that each level's board has a
+// seed of its own.
+const mazeSeedStride = 1000003
+
+// renderMaze pre-renders m's background and minimap, dropping the old ones.
+func (g *Game) renderMaze(m *Maze) {
+	forgetImage(g.mazeImage)
+	forgetImage(g.minimap)
+	g.mazeImage = RenderMazeBackground(m)
+	g.minimap = renderMinimap(m)
+}
+
+// setMaze puts m in play, rendering its walls unless the last maze had the
+// same board, and resizing the window if the screen's size changed.
+func (g *Game) setMaze(m *Maze) {
+	if !slices.Equal(m.layout, g.maze.layout) || m.Spawns != g.maze.Spawns {
+		g.renderMaze(m)
+	}
+	w, h := g.screenSize()
+	g.maze = m
+	g.camera.X, g.camera.Y = g.clampCamera(g.camera.X, g.camera.Y)
+	if nw, nh := g.screenSize(); g.windowed && (nw != w || nh != h) {
+		g.applyWindowSize()
 	}
 }
 
 func (g *Game) updateGameOver() {
 	g.stateTimer--
 	if g.stateTimer <= 0 {
+		if g.editor.testing {
+			g.stopTest()
+			return
+		}
 		g.state = StateTitle
 	}
 }
@@ -201,34 +439,52 @@ func (g *Game) checkDotConsumption() {
 	tile := g.maze.TileAt(tx, ty)
 	if tile == TileDot {
 		g.maze.ConsumeDot(tx, ty)
-		g.score += 10
+		g.score += g.rules.DotScore
 		g.sound.PlayChomp()
+		g.spawnFruit()
 	} else if tile == TilePowerPellet {
 		g.maze.ConsumeDot(tx, ty)
-		g.score += 50
+		g.score += g.rules.PowerPelletScore
 		g.sound.PlayPowerUp()
+		g.spawnFruit()
 		g.triggerFrightenedMode()
 	}
 
-	// Extra life at 10,000 points
-	if g.score >= 10000 && !g.extraLifeAwarded {
+	// Extra lives at the rules' thresholds, 10,000 points once by default
+	for g.nextExtraLife > 0 && g.score >= g.nextExtraLife {
 		g.lives++
-		g.extraLifeAwarded = true
+		g.nextExtraLife = g.rules.nextExtraLife(g.nextExtraLife)
 	}
 }
 
-// triggerFrightenedMode sets all non-eaten ghosts to frightened and reverses their direction.
+// triggerFrightenedMode sets all non-eaten ghosts to frightened and reverses
+// their direction. On levels without frightened time they only turn around.
 func (g *Game) triggerFrightenedMode() {
 	g.ghostsEatenCombo = 0
-	g.frightenedTimer = GetDifficulty(g.level).FrightenedTicks
+	d := g.difficulty()
+	g.frightenedTimer = d.FrightenedTicks
+	g.flashCount = d.FlashCount
 	for _, ghost := range g.ghosts {
 		if ghost.Mode != GhostEaten && !ghost.InHouse {
-			ghost.Mode = GhostFrightened
-			ghost.Dir = reverseDir(ghost.Dir)
+			if d.FrightenedTicks > 0 {
+				ghost.Mode = GhostFrightened
+			}
+			ghost.Reverse(g.maze)
 		}
 	}
 }
 
+// frightenedFlashWhite reports whether frightened ghosts should be drawn white.
+// During the final flashCount flashes, ghosts alternate white and blue,
+// starting on white.
+func (g *Game) frightenedFlashWhite() bool {
+	flashTicks := g.flashCount * 2 * FrightenedFlashTicks
+	if g.frightenedTimer <= 0 || g.frightenedTimer > flashTicks {
+		return false
+	}
+	return ((flashTicks-g.frightenedTimer)/FrightenedFlashTicks)%2 == 0
+}
+
 // CheckCollision returns true if Pac-Man and a ghost are within 6 pixels of each other.
 func CheckCollision(p *PacMan, gh *Ghost) bool {
 	dx := p.X - gh.X
@@ -239,7 +495,7 @@ func CheckCollision(p *PacMan, gh *Ghost) bool {
 
 // ghostEatScore returns the score for eating the next ghost in the combo.
 func (g *Game) ghostEatScore() int {
-	return 200 << g.ghostsEatenCombo
+	return g.rules.GhostScore << g.ghostsEatenCombo
 }
 
 // checkGhostCollisions checks for Pac-Man colliding with any ghost.
@@ -251,6 +507,9 @@ func (g *Game) checkGhostCollisions() {
 		if !CheckCollision(g.pacman, ghost) {
 			continue
 		}
+		if ghost.Mode != GhostFrightened && g.invincible() {
+			continue
+		}
 		if ghost.Mode == GhostFrightened {
 			g.score += g.ghostEatScore()
 			g.ghostsEatenCombo++
@@ -261,7 +520,7 @@ func (g *Game) checkGhostCollisions() {
 			g.pacman.Alive = false
 			g.lives--
 			g.state = StateDeath
-			g.stateTimer = 120
+			g.stateTimer = g.rules.DeathTicks
 			g.sound.PlayDeath()
 			return
 		}
@@ -269,24 +528,61 @@ func (g *Game) checkGhostCollisions() {
 }
 
 func (g *Game) Draw(screen *ebiten.Image) {
-	white := color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF}
+	w, h := g.screenSize()
+	if g.frame == nil || g.frame.Bounds().Dx() != w || g.frame.Bounds().Dy() != h {
+		if g.frame != nil {
+			g.frame.Deallocate()
+		}
+		g.frame = ebiten.NewImage(w, h)
+	}
+	g.DrawTo(g.frame)
+	g.drawConsole(g.frame)
+	// Only on the window, so it stays out of screenshots and recordings
+	if g.recorder != nil {
+		r := g.viewRect()
+		DrawTextWith(g.frame, tr(msgRecording), w/2, r.Max.Y+4, &TextOptions{Color: theme.Palette.Highlight, Align: AlignCenter})
+	}
+	g.present(screen, g.frame)
+}
+
+// DrawTo draws the current frame on any canvas, such as a SoftCanvas for
+// screenshots and recordings.
+func (g *Game) DrawTo(screen Canvas) {
+	centered := &TextOptions{Color: theme.Palette.Text, Align: AlignCenter}
+	screen.Fill(theme.Palette.Background)
+	// Messages go across the view, on the fruit's row and Blinky's, where
+	// the arcade shows them
+	r, spawns := g.viewRect(), g.maze.Spawns
+	midX := (r.Min.X + r.Max.X) / 2
+	rowY := func(row int) int {
+		_, y := g.toScreen(0, float64(row*TileSize))
+		return int(y)
+	}
 
 	switch g.state {
 	case StateTitle:
-		DrawText(screen, "GO PAC-MAN", 62, 100, white)
-		DrawText(screen, "PRESS SPACE", 56, 140, white)
-		DrawText(screen, "TO START", 68, 155, white)
+		g.drawTitle(screen)
+		return
+
+	case StateSettings:
+		g.drawSettings(screen)
+		return
+
+	case StateEditor:
+		g.drawEditor(screen)
 		return
 
 	case StateGameOver:
 		g.drawMaze(screen)
-		DrawText(screen, "GAME OVER", 65, 160, white)
-		DrawHUD(screen, g.score, g.highScore, g.lives, g.level)
+		DrawTextWith(screen, tr(msgGameOver), midX, rowY(spawns.Fruit.Y), centered)
+		g.maskView(screen)
+		g.drawHUD(screen)
 		return
 	}
 
 	// All other states draw the maze and entities
 	g.drawMaze(screen)
+	g.drawFruit(screen)
 
 	// Draw ghosts (not during death)
 	if g.state != StateDeath {
@@ -303,77 +599,102 @@ func (g *Game) Draw(screen *ebiten.Image) {
 		g.drawPacMan(screen)
 	}
 
-	// Draw HUD
-	DrawHUD(screen, g.score, g.highScore, g.lives, g.level)
+	if g.debugOverlay {
+		g.drawDebugOverlay(screen)
+	}
+
+	// Draw HUD, over whatever of a large maze lies outside the view
+	g.maskView(screen)
+	g.drawHUD(screen)
 
 	// State-specific overlays
 	switch g.state {
 	case StateReady:
-		DrawText(screen, "READY!", 85, 164, color.RGBA{R: 0xFF, G: 0xFF, B: 0x00, A: 0xFF})
+		if g.players == 2 {
+			DrawTextWith(screen, fmt.Sprintf(tr(msgPlayerTurn), g.current+1), midX, rowY(spawns.Ghosts[Blinky].Y)+4, centered)
+		}
+		DrawTextWith(screen, tr(msgReady), midX, rowY(spawns.Fruit.Y)+4, &TextOptions{Color: theme.Palette.Highlight, Align: AlignCenter})
 	case StateLevelClear:
 		// Flash walls: alternate white/blue every 15 ticks
 		// (handled in drawMaze via tickCount)
 	}
 }
 
-// drawMaze draws all maze tiles.
-func (g *Game) drawMaze(screen *ebiten.Image) {
-	for y := 0; y < MazeRows; y++ {
-		for x := 0; x < MazeCols; x++ {
+// drawHUD draws the HUD, with the minimap when it is turned on.
+func (g *Game) drawHUD(screen Canvas) {
+	DrawHUD(screen, g.hud())
+	if g.settings.Minimap {
+		g.drawMinimap(screen)
+	}
+}
+
+// drawMaze draws the pre-rendered maze walls, then the remaining dots and power pellets.
+func (g *Game) drawMaze(screen Canvas) {
+	// Flash walls during level clear
+	view := g.viewGeoM()
+	strobeOff := g.state == StateLevelClear && (g.stateTimer/15)%2 == 1
+	if !strobeOff || g.settings.ReducedFlashing {
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Concat(view)
+		screen.DrawImage(g.mazeImage, op)
+	}
+
+	for y := 0; y < g.maze.Height; y++ {
+		for x := 0; x < g.maze.Width; x++ {
 			var tile *ebiten.Image
 			switch g.maze.TileAt(x, y) {
-			case TileWall:
-				// Flash walls during level clear
-				if g.state == StateLevelClear && (g.stateTimer/15)%2 == 1 {
-					tile = sprites.Empty // flash to black
-				} else {
-					tile = sprites.Wall
-				}
 			case TileDot:
 				tile = sprites.Dot
 			case TilePowerPellet:
 				// Blink power pellets every 15 ticks
-				if (g.tickCount/15)%2 == 0 {
+				if (g.tickCount/15)%2 == 0 || g.settings.ReducedFlashing {
 					tile = sprites.PowerPellet
-				} else {
-					tile = sprites.Empty
 				}
-			case TileEmpty:
-				tile = sprites.Empty
-			case TileGhostHouse:
-				tile = sprites.Empty
-			case TileGhostDoor:
-				tile = sprites.GhostDoor
-			default:
-				tile = sprites.Empty
+			}
+			if tile == nil {
+				continue
 			}
 			op := &ebiten.DrawImageOptions{}
-			op.GeoM.Translate(float64(x*TileSize), float64(y*TileSize+HUDTopRows*TileSize))
+			op.GeoM.Translate(float64(x*TileSize), float64(y*TileSize))
+			op.GeoM.Concat(view)
 			screen.DrawImage(tile, op)
 		}
 	}
 }
 
-// drawGhost draws a ghost sprite based on its current mode.
-func (g *Game) drawGhost(screen *ebiten.Image, ghost *Ghost) {
+// drawGhost draws a ghost sprite based on its current mode, direction and skirt animation.
+func (g *Game) drawGhost(screen Canvas, ghost *Ghost) {
+	// Alternate skirt frames every 8 ticks
+	frame := (g.tickCount / 8) % GhostAnimFrames
+
 	var sprite *ebiten.Image
 	switch ghost.Mode {
 	case GhostFrightened:
-		sprite = sprites.GhostFrightened
+		if g.frightenedFlashWhite() {
+			sprite = sprites.GhostFrightenedFlash[frame]
+		} else {
+			sprite = sprites.GhostFrightened[frame]
+		}
 	case GhostEaten:
-		sprite = sprites.GhostEyes
+		sprite = sprites.GhostEyes[ghost.Dir]
 	default:
-		sprite = sprites.GhostSprites[ghost.ID]
+		sprite = sprites.GhostSprites[ghost.ID][ghost.Dir][frame]
 	}
 
 	op := &ebiten.DrawImageOptions{}
 	op.GeoM.Translate(-6, -6)
-	op.GeoM.Translate(ghost.X, ghost.Y+float64(HUDTopRows*TileSize))
+	op.GeoM.Translate(ghost.X, ghost.Y)
+	op.GeoM.Concat(g.viewGeoM())
 	screen.DrawImage(sprite, op)
+
+	// Letter overlay so ghosts can be told apart without relying on color
+	if g.settings.GhostLetters && ghost.Mode != GhostFrightened && ghost.Mode != GhostEaten {
+		screen.DrawImage(sprites.GhostLetters[ghost.ID], op)
+	}
 }
 
 // drawPacMan draws the Pac-Man sprite with appropriate rotation/flip for its direction.
-func (g *Game) drawPacMan(screen *ebiten.Image) {
+func (g *Game) drawPacMan(screen Canvas) {
 	p := g.pacman
 	frame := sprites.PacManFrames[p.AnimFrame]
 
@@ -390,12 +711,13 @@ func (g *Game) drawPacMan(screen *ebiten.Image) {
 	case DirRight, DirNone:
 	}
 
-	op.GeoM.Translate(p.X, p.Y+float64(HUDTopRows*TileSize))
+	op.GeoM.Translate(p.X, p.Y)
+	op.GeoM.Concat(g.viewGeoM())
 	screen.DrawImage(frame, op)
 }
 
 // drawPacManDeath draws the death animation frame at Pac-Man's position.
-func (g *Game) drawPacManDeath(screen *ebiten.Image) {
+func (g *Game) drawPacManDeath(screen Canvas) {
 	p := g.pacman
 	frame := p.DeathFrame
 	if frame < 0 {
@@ -408,10 +730,7 @@ func (g *Game) drawPacManDeath(screen *ebiten.Image) {
 
 	op := &ebiten.DrawImageOptions{}
 	op.GeoM.Translate(-6, -6)
-	op.GeoM.Translate(p.X, p.Y+float64(HUDTopRows*TileSize))
+	op.GeoM.Translate(p.X, p.Y)
+	op.GeoM.Concat(g.viewGeoM())
 	screen.DrawImage(sprite, op)
 }
-
-func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
-	return ScreenWidth, ScreenHeight
-}
@@ -10,6 +10,21 @@ const (
 	GhostEaten
 )
 
+// String returns the mode's lowercase name.
+func (m GhostMode) String() string {
+	switch m {
+	case GhostChase:
+		return "chase"
+	case GhostScatter:
+		return "scatter"
+	case GhostFrightened:
+		return "frightened"
+	case GhostEaten:
+		return "eaten"
+	}
+	return "unknown"
+}
+
 // GhostID identifies each of the four ghosts.
 type GhostID int
 
@@ -31,49 +46,51 @@ type Ghost struct {
 	SpawnY    int
 	ScatterX  int // scatter corner target tile
 	ScatterY  int
+	ExitX     int // tile just outside the ghost house door
+	ExitY     int
+	HomeX     int // tile in the house where eaten ghosts revive
+	HomeY     int
 	InHouse   bool // still in ghost house
 	ExitTimer int  // ticks until ghost leaves house
 
 	lastDecisionTX int // tile where last direction decision was made
 	lastDecisionTY int
+
+	// The last decision, shown by the debug overlay
+	targetTX, targetTY int  // tile the ghost headed for
+	hasTarget          bool // false when frightened ghosts turn at random
+	decisionDir        Direction
 }
 
-// NewGhosts creates all four ghosts at their starting positions.
+// NewGhosts creates all four ghosts at their starting positions in the
+// classic maze.
 func NewGhosts() [4]*Ghost {
-	return [4]*Ghost{
-		{
-			ID: Blinky, Speed: 1.3,
-			X: float64(14*TileSize + TileSize/2), Y: float64(11*TileSize + TileSize/2),
-			SpawnX: 14, SpawnY: 11,
-			ScatterX: 25, ScatterY: 0,
-			Dir: DirLeft, InHouse: false, ExitTimer: 0,
-			lastDecisionTX: -1, lastDecisionTY: -1,
-		},
-		{
-			ID: Pinky, Speed: 1.3,
-			X: float64(12*TileSize + TileSize/2), Y: float64(14*TileSize + TileSize/2),
-			SpawnX: 12, SpawnY: 14,
-			ScatterX: 2, ScatterY: 0,
-			Dir: DirDown, InHouse: true, ExitTimer: 0,
-			lastDecisionTX: -1, lastDecisionTY: -1,
-		},
-		{
-			ID: Inky, Speed: 1.3,
-			X: float64(14*TileSize + TileSize/2), Y: float64(14*TileSize + TileSize/2),
-			SpawnX: 14, SpawnY: 14,
-			ScatterX: 27, ScatterY: 30,
-			Dir: DirUp, InHouse: true, ExitTimer: 300,
-			lastDecisionTX: -1, lastDecisionTY: -1,
-		},
-		{
-			ID: Clyde, Speed: 1.3,
-			X: float64(16*TileSize + TileSize/2), Y: float64(14*TileSize + TileSize/2),
-			SpawnX: 16, SpawnY: 14,
-			ScatterX: 0, ScatterY: 30,
-			Dir: DirUp, InHouse: true, ExitTimer: 600,
+	return NewGhostsAt(classicSpawns)
+}
+
+// ghostExitTicks is how long each ghost waits in the house at the start.
+var ghostExitTicks = [4]int{0, 0, 300, 600}
+
+// NewGhostsAt creates all four ghosts at a board's spawns. Blinky starts
+// outside the house, heading left; the others wait inside it.
+func NewGhostsAt(s Spawns) [4]*Ghost {
+	var ghosts [4]*Ghost
+	for id := range ghosts {
+		spawn := s.Ghosts[id]
+		ghosts[id] = &Ghost{
+			ID: GhostID(id), Speed: 1.3,
+			X: float64(spawn.X*TileSize + TileSize/2), Y: float64(spawn.Y*TileSize + TileSize/2),
+			SpawnX: spawn.X, SpawnY: spawn.Y,
+			ScatterX: s.Scatter[id].X, ScatterY: s.Scatter[id].Y,
+			ExitX: s.Ghosts[Blinky].X, ExitY: s.Ghosts[Blinky].Y,
+			HomeX: s.Ghosts[Inky].X, HomeY: s.Ghosts[Inky].Y,
+			Dir: DirUp, InHouse: id != int(Blinky), ExitTimer: ghostExitTicks[id],
 			lastDecisionTX: -1, lastDecisionTY: -1,
-		},
+		}
 	}
+	ghosts[Blinky].Dir = DirLeft
+	ghosts[Pinky].Dir = DirDown
+	return ghosts
 }
 
 // TileX returns the ghost's current tile column.
@@ -82,6 +99,16 @@ func (g *Ghost) TileX() int { return int(g.X) / TileSize }
 // TileY returns the ghost's current tile row.
 func (g *Ghost) TileY() int { return int(g.Y) / TileSize }
 
+// Reverse turns the ghost around, as ghosts do on becoming frightened,
+// unless the way back from its tile is closed to it: against a one-way door,
+// or into a wall beside the portal it came out of.
+func (g *Ghost) Reverse(m *Maze) {
+	back := reverseDir(g.Dir)
+	if _, _, ok := m.CanGhostMove(g.TileX(), g.TileY(), back); ok {
+		g.Dir = back
+	}
+}
+
 // ResetToSpawn returns the ghost to its initial spawn position.
 func (g *Ghost) ResetToSpawn() {
 	g.X = float64(g.SpawnX*TileSize + TileSize/2)
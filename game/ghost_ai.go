@@ -5,8 +5,18 @@ import (
 	"math/rand"
 )
 
+// rng drives every random choice the ghosts make. Each game seeds it, so a
+// replay of the game makes the same choices.
+var rng = rand.New(rand.NewSource(1))
+
+// seedRNG restarts the ghosts' random choices from seed.
+func seedRNG(seed int64) {
+	rng.Seed(seed)
+}
+
 // BFS finds the shortest path from (startX, startY) to (targetX, targetY)
-// using breadth-first search on the tile grid. Returns a slice of directions.
+// using breadth-first search on the tile grid, through tunnels and portals
+// and along one-way doors. Returns a slice of directions.
 // Returns empty slice if no path found or start is not passable.
 func BFS(m *Maze, startX, startY, targetX, targetY int) []Direction {
 	if !m.IsPassableForGhost(startX, startY) {
@@ -33,9 +43,9 @@ func BFS(m *Maze, startX, startY, targetX, targetY int) []Direction {
 		queue = queue[1:]
 
 		for _, d := range dirs {
-			nx, ny := nextTile(cur.x, cur.y, d)
+			nx, ny, ok := m.CanGhostMove(cur.x, cur.y, d)
 			np := point{nx, ny}
-			if visited[np] || !m.IsPassableForGhost(nx, ny) {
+			if visited[np] || !ok {
 				continue
 			}
 			visited[np] = true
@@ -83,25 +93,26 @@ type modePhase struct {
 	ticks int // -1 for infinite
 }
 
-// NewModeTimer creates a mode timer for the given level.
-func NewModeTimer(level int) *ModeTimer {
-	// Level 1 phases: scatter 7s, chase 20s, scatter 7s, chase 20s, scatter 5s, chase forever
-	// Scale scatter durations down for higher levels
-	scatterScale := 1.0
-	if level > 1 {
-		scatterScale = math.Max(0.4, 1.0-float64(level-1)*0.07)
+// NewModeTimer creates a mode timer running a difficulty schedule: phases of
+// scatter and chase, alternating and starting with scatter, each lasting the
+// given ticks. The phase after the last one lasts forever.
+func NewModeTimer(schedule []int) *ModeTimer {
+	mt := &ModeTimer{}
+	mode := GhostScatter
+	for _, ticks := range schedule {
+		mt.phases = append(mt.phases, modePhase{mode, ticks})
+		mode = otherMode(mode)
 	}
+	mt.phases = append(mt.phases, modePhase{mode, -1})
+	return mt
+}
 
-	return &ModeTimer{
-		phases: []modePhase{
-			{GhostScatter, int(420 * scatterScale)},
-			{GhostChase, 1200},
-			{GhostScatter, int(420 * scatterScale)},
-			{GhostChase, 1200},
-			{GhostScatter, int(300 * scatterScale)},
-			{GhostChase, -1}, // forever
-		},
+// otherMode swaps scatter and chase.
+func otherMode(m GhostMode) GhostMode {
+	if m == GhostScatter {
+		return GhostChase
 	}
+	return GhostScatter
 }
 
 // Tick advances the mode timer by one tick.
@@ -128,6 +139,20 @@ func (mt *ModeTimer) CurrentMode() GhostMode {
 	return mt.phases[mt.currentPhase].mode
 }
 
+// Phase returns the index of the current phase and the number of phases.
+func (mt *ModeTimer) Phase() (current, total int) {
+	return mt.currentPhase, len(mt.phases)
+}
+
+// Remaining returns the ticks left in the current phase, or -1 if it lasts
+// forever.
+func (mt *ModeTimer) Remaining() int {
+	if mt.currentPhase >= len(mt.phases) || mt.phases[mt.currentPhase].ticks == -1 {
+		return -1
+	}
+	return mt.phases[mt.currentPhase].ticks - mt.ticksInPhase
+}
+
 // Reset restarts the mode timer.
 func (mt *ModeTimer) Reset() {
 	mt.currentPhase = 0
@@ -150,8 +175,9 @@ func (g *Ghost) ChooseDirection(m *Maze, targetX, targetY int) Direction {
 		if d == reverse {
 			continue // never reverse
 		}
-		nx, ny := nextTile(tx, ty, d)
-		if !m.IsPassableForGhost(nx, ny) {
+		// Measured from where the move leads, so from a portal's pair
+		nx, ny, ok := m.CanGhostMove(tx, ty, d)
+		if !ok {
 			continue
 		}
 		dx := float64(nx - targetX)
@@ -175,15 +201,22 @@ func (g *Ghost) ChooseRandomDirection(m *Maze) Direction {
 		if d == reverse {
 			continue
 		}
-		nx, ny := nextTile(tx, ty, d)
-		if m.IsPassableForGhost(nx, ny) {
+		if _, _, ok := m.CanGhostMove(tx, ty, d); ok {
 			valid = append(valid, d)
 		}
 	}
 	if len(valid) == 0 {
 		return DirNone
 	}
-	return valid[rand.Intn(len(valid))]
+	return valid[rng.Intn(len(valid))]
+}
+
+// headFor turns the ghost towards a target tile and remembers the decision.
+func (g *Ghost) headFor(m *Maze, targetX, targetY int) {
+	g.Dir = g.ChooseDirection(m, targetX, targetY)
+	g.targetTX, g.targetTY = targetX, targetY
+	g.hasTarget = true
+	g.decisionDir = g.Dir
 }
 
 // isAtTileCenter checks if the ghost is within Speed pixels of the nearest tile center.
@@ -201,9 +234,9 @@ func UpdateGhost(g *Ghost, m *Maze, pacman *PacMan, globalMode GhostMode) {
 			g.ExitTimer--
 			return
 		}
-		// Move toward ghost house exit (tile 14, 11 — just above the door)
-		exitX := float64(14*TileSize + TileSize/2)
-		exitY := float64(11*TileSize + TileSize/2)
+		// Move toward the ghost house exit, just above the door
+		exitX := float64(g.ExitX*TileSize + TileSize/2)
+		exitY := float64(g.ExitY*TileSize + TileSize/2)
 		dx := exitX - g.X
 		dy := exitY - g.Y
 		dist := math.Sqrt(dx*dx + dy*dy)
@@ -243,30 +276,33 @@ func UpdateGhost(g *Ghost, m *Maze, pacman *PacMan, globalMode GhostMode) {
 		switch mode {
 		case GhostChase:
 			// Target Pac-Man with small random offset to prevent clumping
-			targetX := pacman.TileX() + (rand.Intn(5) - 2)
-			targetY := pacman.TileY() + (rand.Intn(5) - 2)
-			g.Dir = g.ChooseDirection(m, targetX, targetY)
+			targetX := pacman.TileX() + (rng.Intn(5) - 2)
+			targetY := pacman.TileY() + (rng.Intn(5) - 2)
+			g.headFor(m, targetX, targetY)
 		case GhostScatter:
-			g.Dir = g.ChooseDirection(m, g.ScatterX, g.ScatterY)
+			g.headFor(m, g.ScatterX, g.ScatterY)
 		case GhostFrightened:
 			g.Dir = g.ChooseRandomDirection(m)
+			g.hasTarget = false
+			g.decisionDir = g.Dir
 		case GhostEaten:
 			// Head back to ghost house entrance
-			if g.TileX() == 14 && g.TileY() == 11 {
+			if g.TileX() == g.ExitX && g.TileY() == g.ExitY {
 				// Arrived at house entrance, re-enter
 				g.InHouse = true
 				g.ExitTimer = 0 // exit immediately after respawn
 				g.Mode = GhostScatter
-				g.X = float64(14*TileSize + TileSize/2)
-				g.Y = float64(14*TileSize + TileSize/2)
+				g.X = float64(g.HomeX*TileSize + TileSize/2)
+				g.Y = float64(g.HomeY*TileSize + TileSize/2)
 				return
 			}
-			g.Dir = g.ChooseDirection(m, 14, 11)
+			g.headFor(m, g.ExitX, g.ExitY)
 		}
 	}
 
 move:
-	// Move in current direction
+	// Move in current direction, through tunnels and portals
+	tx, ty := g.TileX(), g.TileY()
 	switch g.Dir {
 	case DirUp:
 		g.Y -= g.Speed
@@ -277,11 +313,5 @@ move:
 	case DirRight:
 		g.X += g.Speed
 	}
-
-	// Tunnel wrapping
-	if g.X < 0 {
-		g.X += float64(MazeCols * TileSize)
-	} else if g.X >= float64(MazeCols*TileSize) {
-		g.X -= float64(MazeCols * TileSize)
-	}
+	crossTile(m, &g.X, &g.Y, tx, ty, g.Dir)
 }
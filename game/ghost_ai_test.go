@@ -1,6 +1,9 @@
 package game
 
-import "testing"
+import (
+	"slices"
+	"testing"
+)
 
 func TestBFS(t *testing.T) {
 	m := NewMaze()
@@ -21,7 +24,7 @@ func TestBFSBlocked(t *testing.T) {
 }
 
 func TestGhostModeTimer(t *testing.T) {
-	mt := NewModeTimer(1) // level 1
+	mt := NewModeTimer(GetDifficulty(1).Schedule) // level 1
 	if mt.CurrentMode() != GhostScatter {
 		t.Error("should start in scatter mode")
 	}
@@ -35,7 +38,7 @@ func TestGhostModeTimer(t *testing.T) {
 }
 
 func TestGhostModeTimerFullCycle(t *testing.T) {
-	mt := NewModeTimer(1)
+	mt := NewModeTimer(GetDifficulty(1).Schedule)
 	// Scatter 420 -> Chase 1200 -> Scatter 420 -> Chase 1200 -> Scatter 300 -> Chase forever
 	// After all scatter+chase phases, should be in chase permanently
 	total := 420 + 1200 + 420 + 1200 + 300 + 1
@@ -64,3 +67,55 @@ func TestGhostChooseDirection(t *testing.T) {
 		t.Error("ghost should not reverse direction")
 	}
 }
+
+func TestModeTimerPhaseAndRemaining(t *testing.T) {
+	mt := NewModeTimer(GetDifficulty(1).Schedule)
+	for i := 0; i < 20; i++ {
+		mt.Tick()
+	}
+	if cur, total := mt.Phase(); cur != 0 || total != 6 {
+		t.Errorf("phase: got %d of %d, want 0 of 6", cur, total)
+	}
+	if got := mt.Remaining(); got != 400 {
+		t.Errorf("remaining: got %d, want 400", got)
+	}
+	for i := 0; i < 420+1200+420+1200+300; i++ {
+		mt.Tick()
+	}
+	if got := mt.Remaining(); got != -1 {
+		t.Errorf("last chase phase: got %d remaining, want -1 for forever", got)
+	}
+}
+
+func TestGhostRemembersDecision(t *testing.T) {
+	m := NewMaze()
+	ghosts := NewGhosts()
+	blinky := ghosts[Blinky]
+	UpdateGhost(blinky, m, NewPacMan(), GhostScatter)
+	if !blinky.hasTarget || blinky.targetTX != blinky.ScatterX || blinky.targetTY != blinky.ScatterY {
+		t.Errorf("scatter target: got %d,%d (%v), want the scatter corner %d,%d",
+			blinky.targetTX, blinky.targetTY, blinky.hasTarget, blinky.ScatterX, blinky.ScatterY)
+	}
+	if blinky.decisionDir != blinky.Dir {
+		t.Errorf("decision: got %v, want %v", blinky.decisionDir, blinky.Dir)
+	}
+
+	blinky = NewGhosts()[Blinky]
+	blinky.Mode = GhostFrightened
+	UpdateGhost(blinky, m, NewPacMan(), GhostScatter)
+	if blinky.hasTarget {
+		t.Error("a frightened ghost turns at random and has no target")
+	}
+}
+
+func TestBFSThroughPortalAndOneWay(t *testing.T) {
+	m := NewMazeFromLayout(portalMaze)
+	if got := BFS(m, 2, 1, 5, 1); !slices.Equal(got, []Direction{DirLeft}) {
+		t.Errorf("got %v from 2,1 to 5,1, want one step left through the portal", got)
+	}
+	// The one-way door at 2,2 only lets ghosts through to the right
+	want := []Direction{DirUp, DirRight, DirRight, DirDown}
+	if got := BFS(m, 3, 2, 1, 2); !slices.Equal(got, want) {
+		t.Errorf("got %v from 3,2 to 1,2, want %v round through the portal", got, want)
+	}
+}
@@ -0,0 +1,68 @@
+//go:build golden
+
+// The screen filters are shaders, which only run on the GPU, so their golden
+// test reads pixels back from inside Ebitengine's game loop. That needs a
+// display, which is why it is behind the golden build tag:
+//
+//	go test -tags golden ./game -update   to write the goldens on a machine with a GPU
+//	go test -tags golden ./game           (xvfb-run go test ... on a headless machine)
+//
+// Drivers interpolate and round shader output differently, so the filters'
+// goldens allow a wider tolerance than the frames', and are skipped until
+// they have been written.
+package game
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// testLoop runs the tests from inside the game loop, where pixels can be read.
+type testLoop struct {
+	m    *testing.M
+	code int
+}
+
+func (l *testLoop) Update() error {
+	l.code = l.m.Run()
+	return ebiten.Termination
+}
+
+func (l *testLoop) Draw(*ebiten.Image) {}
+
+func (l *testLoop) Layout(int, int) (int, int) {
+	return MinScreenWidth, MinScreenHeight
+}
+
+func TestMain(m *testing.M) {
+	l := &testLoop{m: m, code: 1}
+	if err := ebiten.RunGame(l); err != nil {
+		panic(err)
+	}
+	os.Exit(l.code)
+}
+
+// filterTolerance is how far each channel of a filter's output may stray from
+// its golden.
+const filterTolerance = 12
+
+// TestGoldenScreenFilters checks the letterboxed, filtered output in a window
+// twice the size of the game screen plus a border.
+func TestGoldenScreenFilters(t *testing.T) {
+	for _, filter := range ScreenFilters {
+		t.Run(filter, func(t *testing.T) {
+			g := New()
+			g.settings.ScreenFilter = filter
+			g.state = StatePlaying
+			name := "filter-" + filter
+			if _, err := os.Stat(goldenPath(name)); os.IsNotExist(err) && !*updateGolden {
+				t.Skipf("no golden for %s; write it with -update", name)
+			}
+			screen := ebiten.NewImage(2*MinScreenWidth+20, 2*MinScreenHeight+20)
+			g.Draw(screen)
+			checkGoldenWithin(t, name, ReadImage(screen), filterTolerance)
+		})
+	}
+}
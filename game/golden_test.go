@@ -0,0 +1,188 @@
+// Golden-image tests render sprites and whole frames of known game states in
+// memory and compare them pixel by pixel with the PNGs in testdata/frames:
+//
+//	go test ./game -update   after an intended rendering change
+package game
+
+import (
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// goldenPath returns where the golden image called name is kept.
+func goldenPath(name string) string {
+	return filepath.Join("testdata", "frames", name+".png")
+}
+
+// checkGolden compares img with testdata/frames/<name>.png, allowing channels
+// to differ by a couple of steps.
+func checkGolden(t *testing.T, name string, img *image.RGBA) {
+	t.Helper()
+	checkGoldenWithin(t, name, img, 2)
+}
+
+// checkGoldenWithin compares img with testdata/frames/<name>.png, allowing
+// each 8-bit channel to differ by tolerance.
+func checkGoldenWithin(t *testing.T, name string, img *image.RGBA, tolerance int) {
+	t.Helper()
+	path := goldenPath(name)
+	if *updateGolden {
+		writePNG(t, path, img)
+		t.Logf("wrote %s", path)
+		return
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("%v (run with -update to create it)", err)
+	}
+	defer f.Close()
+	golden, err := png.Decode(f)
+	if err != nil {
+		t.Fatalf("%s: %v", path, err)
+	}
+	if golden.Bounds() != img.Bounds() {
+		t.Fatalf("size: got %v, golden %v", img.Bounds(), golden.Bounds())
+	}
+
+	diff := 0
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			r1, g1, b1, a1 := img.At(x, y).RGBA()
+			r2, g2, b2, a2 := golden.At(x, y).RGBA()
+			for _, d := range [4][2]uint32{{r1, r2}, {g1, g2}, {b1, b2}, {a1, a2}} {
+				if v1, v2 := int(d[0]>>8), int(d[1]>>8); v1-v2 > tolerance || v2-v1 > tolerance {
+					diff++
+					break
+				}
+			}
+		}
+	}
+	if diff > 0 {
+		got := filepath.Join(os.TempDir(), "go-pacman-golden", name+".png")
+		writePNG(t, got, img)
+		t.Errorf("%d pixels differ from %s; this run's image is at %s", diff, path, got)
+	}
+}
+
+func writePNG(t *testing.T, path string, img image.Image) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGoldenSpriteSheet(t *testing.T) {
+	New()
+	checkGolden(t, "spritesheet", SpriteSheetImage(false))
+	checkGolden(t, "spritesheet-labelled", SpriteSheetImage(true))
+}
+
+// placePacMan puts Pac-Man at the center of a tile, facing dir.
+func placePacMan(g *Game, x, y int, dir Direction) {
+	g.pacman.X = float64(x*TileSize + TileSize/2)
+	g.pacman.Y = float64(y*TileSize + TileSize/2)
+	g.pacman.Dir = dir
+}
+
+func TestGoldenFrames(t *testing.T) {
+	defer func() { theme = Themes[0] }()
+
+	tests := []struct {
+		name  string
+		setup func(t *testing.T, g *Game)
+	}{
+		{"title", func(t *testing.T, g *Game) {}},
+		{"settings", func(t *testing.T, g *Game) {
+			g.state = StateSettings
+			g.settingsCursor = 2
+		}},
+		{"ready", func(t *testing.T, g *Game) {
+			g.state = StateReady
+		}},
+		{"playing", func(t *testing.T, g *Game) {
+			g.state = StatePlaying
+			for x := 1; x <= 6; x++ {
+				g.maze.ConsumeDot(x, 1)
+			}
+			placePacMan(g, 6, 1, DirRight)
+			g.pacman.AnimFrame = 1
+			g.score = 60
+		}},
+		{"pacman-up", func(t *testing.T, g *Game) {
+			g.state = StatePlaying
+			placePacMan(g, 1, 5, DirUp)
+			g.pacman.AnimFrame = 2
+		}},
+		{"pacman-down", func(t *testing.T, g *Game) {
+			g.state = StatePlaying
+			placePacMan(g, 1, 5, DirDown)
+			g.pacman.AnimFrame = 2
+		}},
+		{"pacman-left", func(t *testing.T, g *Game) {
+			g.state = StatePlaying
+			placePacMan(g, 1, 5, DirLeft)
+			g.pacman.AnimFrame = 2
+		}},
+		{"frightened", func(t *testing.T, g *Game) {
+			g.state = StatePlaying
+			g.tickCount = 8 // second skirt frame
+			g.triggerFrightenedMode()
+			g.ghosts[1].Mode = GhostEaten
+			g.ghosts[2].InHouse = false
+			g.ghosts[2].Mode = GhostFrightened
+		}},
+		{"frightened-flash", func(t *testing.T, g *Game) {
+			g.state = StatePlaying
+			g.triggerFrightenedMode()
+			g.frightenedTimer = g.flashCount * 2 * FrightenedFlashTicks
+		}},
+		{"death", func(t *testing.T, g *Game) {
+			g.state = StateDeath
+			g.pacman.DeathFrame = 5
+		}},
+		{"level-clear-strobe", func(t *testing.T, g *Game) {
+			g.state = StateLevelClear
+			g.stateTimer = 15 // walls off
+		}},
+		{"game-over", func(t *testing.T, g *Game) {
+			g.state = StateGameOver
+			g.score, g.highScores = 1230, HighScores{ModeClassic: {4560}}
+			g.lives = 0
+		}},
+		{"colorsafe-letters", func(t *testing.T, g *Game) {
+			ts, err := ThemeByID("colorsafe")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := g.SetTheme(ts); err != nil {
+				t.Fatal(err)
+			}
+			g.settings.GhostLetters = true
+			g.state = StatePlaying
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			theme = Themes[0]
+			g := New()
+			tt.setup(t, g)
+			checkGolden(t, tt.name, g.Frame())
+		})
+	}
+}
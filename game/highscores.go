@@ -0,0 +1,123 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// highScoreEntries is how many scores each mode's table keeps.
+const highScoreEntries = 5
+
+// HighScores holds each mode's best scores, highest first, by mode ID. They
+// are saved as JSON between runs.
+type HighScores map[string][]int
+
+// Add enters a score in a mode's table and returns its place, counting from
+// 1, or 0 if it is too low to make the table.
+func (h HighScores) Add(mode string, score int) int {
+	if score <= 0 {
+		return 0
+	}
+	table := h[mode]
+	// Below any equal scores, so the earlier one stays ahead
+	i := 0
+	for i < len(table) && table[i] >= score {
+		i++
+	}
+	if i >= highScoreEntries {
+		return 0
+	}
+	table = slices.Insert(table, i, score)
+	h[mode] = table[:min(len(table), highScoreEntries)]
+	return i + 1
+}
+
+// Best returns a mode's highest score, or 0 if it has none.
+func (h HighScores) Best(mode string) int {
+	if len(h[mode]) == 0 {
+		return 0
+	}
+	return h[mode][0]
+}
+
+// DefaultHighScoresPath returns where high scores are saved:
+// go-pacman/highscores.json in the user's configuration directory.
+func DefaultHighScoresPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "go-pacman", "highscores.json"), nil
+}
+
+// LoadHighScores reads high scores saved by SaveHighScores. A missing file
+// gives empty tables.
+func LoadHighScores(path string) (HighScores, error) {
+	h := HighScores{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return h, nil
+	}
+	if err != nil {
+		return h, err
+	}
+	if err := json.Unmarshal(data, &h); err != nil {
+		return HighScores{}, fmt.Errorf("high scores %s: %w", path, err)
+	}
+	return h, nil
+}
+
+// SaveHighScores writes high scores to path, creating its directory if
+// needed.
+func SaveHighScores(path string, h HighScores) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// SetHighScores replaces the game's high score tables.
+func (g *Game) SetHighScores(h HighScores) {
+	if h == nil {
+		h = HighScores{}
+	}
+	g.highScores = h
+}
+
+// SetHighScoresPath makes the game save its high scores to path after each
+// game. With no path set, nothing is saved.
+func (g *Game) SetHighScoresPath(path string) {
+	g.highScoresPath = path
+}
+
+// endGame shows GAME OVER and enters the scores in the mode's high score
+// table. Replays are played back without entering their scores again, and
+// editor test games don't count.
+func (g *Game) endGame() {
+	g.state = StateGameOver
+	g.stateTimer = g.rules.GameOverTicks
+	if g.playback || g.editor.testing {
+		return
+	}
+	g.highScores.Add(g.modeID, g.score)
+	if g.players == 2 {
+		g.highScores.Add(g.modeID, g.waiting.score)
+	}
+	if g.highScoresPath == "" {
+		return
+	}
+	// As with settings, a failed save is logged rather than stopping the game
+	if err := SaveHighScores(g.highScoresPath, g.highScores); err != nil {
+		log.Printf("saving high scores: %v", err)
+	}
+}
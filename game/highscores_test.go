@@ -0,0 +1,80 @@
+package game
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestHighScoresAdd(t *testing.T) {
+	h := HighScores{}
+	for _, tt := range []struct {
+		score, place int
+		want         []int
+	}{
+		{300, 1, []int{300}},
+		{0, 0, []int{300}},
+		{500, 1, []int{500, 300}},
+		{300, 3, []int{500, 300, 300}},
+		{100, 4, []int{500, 300, 300, 100}},
+		{400, 2, []int{500, 400, 300, 300, 100}},
+		{50, 0, []int{500, 400, 300, 300, 100}},
+		{200, 5, []int{500, 400, 300, 300, 200}},
+	} {
+		if got := h.Add(ModeClassic, tt.score); got != tt.place {
+			t.Errorf("Add(%d): got place %d, want %d", tt.score, got, tt.place)
+		}
+		if !slices.Equal(h[ModeClassic], tt.want) {
+			t.Errorf("after Add(%d): got %v, want %v", tt.score, h[ModeClassic], tt.want)
+		}
+	}
+	if h.Best(ModeClassic) != 500 || h.Best(ModeSurvival) != 0 {
+		t.Errorf("Best: got %d and %d, want 500 and 0", h.Best(ModeClassic), h.Best(ModeSurvival))
+	}
+}
+
+func TestHighScoresRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "go-pacman", "highscores.json")
+	h, err := LoadHighScores(path)
+	if err != nil || len(h) != 0 {
+		t.Fatalf("missing file: got %v, %v; want empty tables", h, err)
+	}
+	h.Add(ModeEndless, 1200)
+	h.Add(ModeTimeAttack, 800)
+	if err := SaveHighScores(path, h); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadHighScores(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(got[ModeEndless], []int{1200}) || !slices.Equal(got[ModeTimeAttack], []int{800}) {
+		t.Errorf("got %v, want %v", got, h)
+	}
+}
+
+func TestHighScoresPerMode(t *testing.T) {
+	g := New()
+	if err := g.SetMode(ModeEndless); err != nil {
+		t.Fatal(err)
+	}
+	g.startGame(1, 1)
+	g.score = 700
+	g.lives = 1
+	loseLife(g)
+	if g.state != StateGameOver || g.highScores.Best(ModeEndless) != 700 || g.highScores.Best(ModeClassic) != 0 {
+		t.Errorf("got state %v, tables %v; want game over with 700 for endless only", g.state, g.highScores)
+	}
+	if g.hud().HighScore != 700 {
+		t.Errorf("HUD high score: got %d, want the endless table's 700", g.hud().HighScore)
+	}
+
+	// Replays don't enter their scores again
+	g.StartReplay(g.replay)
+	g.score = 900
+	g.lives = 1
+	loseLife(g)
+	if g.highScores.Best(ModeEndless) != 700 {
+		t.Errorf("a replayed game entered a high score: %v", g.highScores)
+	}
+}
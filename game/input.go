@@ -1,19 +1,75 @@
 package game
 
-import "github.com/hajimehoshi/ebiten/v2"
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
 
-// ReadInput reads keyboard input and sets Pac-Man's queued direction.
-func ReadInput(p *PacMan) {
+// ReadInput reads keyboard input and sets Pac-Man's queued direction. The
+// keys are directions on the display, which shows the maze rotated clockwise
+// by rotation degrees; they are turned back into directions in the maze, so
+// up always moves Pac-Man towards the top of the display.
+func ReadInput(p *PacMan, rotation int) {
 	if ebiten.IsKeyPressed(ebiten.KeyArrowUp) || ebiten.IsKeyPressed(ebiten.KeyW) {
-		p.NextDir = DirUp
+		p.NextDir = rotateDir(DirUp, -rotation)
 	}
 	if ebiten.IsKeyPressed(ebiten.KeyArrowDown) || ebiten.IsKeyPressed(ebiten.KeyS) {
-		p.NextDir = DirDown
+		p.NextDir = rotateDir(DirDown, -rotation)
 	}
 	if ebiten.IsKeyPressed(ebiten.KeyArrowLeft) || ebiten.IsKeyPressed(ebiten.KeyA) {
-		p.NextDir = DirLeft
+		p.NextDir = rotateDir(DirLeft, -rotation)
 	}
 	if ebiten.IsKeyPressed(ebiten.KeyArrowRight) || ebiten.IsKeyPressed(ebiten.KeyD) {
-		p.NextDir = DirRight
+		p.NextDir = rotateDir(DirRight, -rotation)
 	}
 }
+
+// arrows are the arrow keys and the directions they point on the display.
+var arrows = [4]struct {
+	key ebiten.Key
+	dir Direction
+}{
+	{ebiten.KeyArrowUp, DirUp},
+	{ebiten.KeyArrowDown, DirDown},
+	{ebiten.KeyArrowLeft, DirLeft},
+	{ebiten.KeyArrowRight, DirRight},
+}
+
+// justPressedArrow returns the direction on the game screen of the arrow key
+// pressed this tick, or DirNone. Like ReadInput, it turns the key back from
+// the display, which shows the game screen rotated by rotation degrees, so
+// menus and the editor follow the keys as they are seen.
+func justPressedArrow(rotation int) Direction {
+	for _, a := range arrows {
+		if inpututil.IsKeyJustPressed(a.key) {
+			return rotateDir(a.dir, -rotation)
+		}
+	}
+	return DirNone
+}
+
+// heldArrows returns the directions on the game screen of the arrow keys
+// held down, turned back from the display like justPressedArrow.
+func heldArrows(rotation int) []Direction {
+	var dirs []Direction
+	for _, a := range arrows {
+		if ebiten.IsKeyPressed(a.key) {
+			dirs = append(dirs, rotateDir(a.dir, -rotation))
+		}
+	}
+	return dirs
+}
+
+// clockwise lists the directions in clockwise order.
+var clockwise = [4]Direction{DirUp, DirRight, DirDown, DirLeft}
+
+// rotateDir turns d clockwise by degrees, a multiple of 90. Negative degrees
+// turn counterclockwise.
+func rotateDir(d Direction, degrees int) Direction {
+	for i, c := range clockwise {
+		if c == d {
+			return clockwise[wrapIndex(i+degrees/90, len(clockwise))]
+		}
+	}
+	return d
+}
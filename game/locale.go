@@ -0,0 +1,171 @@
+package game
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Message IDs for all on-screen text. Each locale file in locales/ maps these
+// IDs to translated strings.
+const (
+	msgLanguageName           = "language_name"
+	msgTitle                  = "title"
+	msgPressStart             = "press_start"
+	msgOpenSettings           = "open_settings"
+	msgReady                  = "ready"
+	msgGameOver               = "game_over"
+	msgPlayerOne              = "player_one"
+	msgPlayerTwo              = "player_two"
+	msgTwoPlayers             = "two_players"
+	msgPlayerTurn             = "player_turn" // takes the player number as %d
+	msgHighScore              = "high_score"
+	msgLevel                  = "level" // takes the level number as %d
+	msgModeClassic            = "mode_classic"
+	msgModeTimeAttack         = "mode_time_attack"
+	msgModeEndless            = "mode_endless"
+	msgModeSurvival           = "mode_survival"
+	msgTime                   = "time" // takes the clock as %s
+	msgWave                   = "wave" // takes the wave number as %d
+	msgOpenEditor             = "open_editor"
+	msgEditorHelp             = "editor_help"
+	msgEditorBrush            = "editor_brush"  // takes the brush name as %s
+	msgEditorMirror           = "editor_mirror" // takes ON or OFF as %s
+	msgEditorTarget           = "editor_target"
+	msgEditorSaved            = "editor_saved"
+	msgBrushWall              = "brush_wall"
+	msgBrushDot               = "brush_dot"
+	msgBrushPowerPellet       = "brush_power_pellet"
+	msgBrushEmpty             = "brush_empty"
+	msgBrushGhostHouse        = "brush_ghost_house"
+	msgBrushGhostDoor         = "brush_ghost_door"
+	msgBrushTunnel            = "brush_tunnel"
+	msgBrushPortal            = "brush_portal"
+	msgBrushOneWay            = "brush_one_way"
+	msgBrushPacMan            = "brush_pacman"
+	msgBrushFruit             = "brush_fruit"
+	msgBrushBlinky            = "brush_blinky"
+	msgBrushPinky             = "brush_pinky"
+	msgBrushInky              = "brush_inky"
+	msgBrushClyde             = "brush_clyde"
+	msgSettings               = "settings"
+	msgSettingsHelp           = "settings_help"
+	msgSettingLanguage        = "setting_language"
+	msgSettingTheme           = "setting_theme"
+	msgSettingGhostLetters    = "setting_ghost_letters"
+	msgSettingReducedFlashing = "setting_reduced_flashing"
+	msgSettingMinimap         = "setting_minimap"
+	msgSettingGameSpeed       = "setting_game_speed"
+	msgSettingMasterVolume    = "setting_master_volume"
+	msgSettingSFXVolume       = "setting_sfx_volume"
+	msgSettingMusicVolume     = "setting_music_volume"
+	msgSettingMute            = "setting_mute"
+	msgSettingWindowScale     = "setting_window_scale"
+	msgSettingFullscreen      = "setting_fullscreen"
+	msgSettingVSync           = "setting_vsync"
+	msgSettingResizable       = "setting_resizable"
+	msgSettingScreenFilter    = "setting_screen_filter"
+	msgFilterNone             = "filter_none"
+	msgFilterScanlines        = "filter_scanlines"
+	msgFilterCRT              = "filter_crt"
+	msgSettingRotation        = "setting_rotation"
+	msgSettingCocktail        = "setting_cocktail"
+	msgRecording              = "recording"
+	msgOn                     = "on"
+	msgOff                    = "off"
+)
+
+// DefaultLanguage is the fallback for missing translations and unknown locales.
+const DefaultLanguage = "en"
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// catalogs maps a language code to its message catalog.
+var catalogs = loadCatalogs()
+
+// language is the active language code.
+var language = DefaultLanguage
+
+// loadCatalogs parses every embedded locale file. The files are compiled into
+// the binary, so a malformed one is a programming error.
+func loadCatalogs() map[string]map[string]string {
+	entries, err := localeFiles.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+	cats := make(map[string]map[string]string)
+	for _, e := range entries {
+		data, err := localeFiles.ReadFile(path.Join("locales", e.Name()))
+		if err != nil {
+			panic(err)
+		}
+		var cat map[string]string
+		if err := json.Unmarshal(data, &cat); err != nil {
+			panic(fmt.Sprintf("locale %s: %v", e.Name(), err))
+		}
+		cats[strings.TrimSuffix(e.Name(), ".json")] = cat
+	}
+	return cats
+}
+
+// Languages returns the available language codes, the default language first.
+func Languages() []string {
+	codes := make([]string, 0, len(catalogs))
+	for code := range catalogs {
+		if code != DefaultLanguage {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+	return append([]string{DefaultLanguage}, codes...)
+}
+
+// SetLanguage makes code the active language.
+func SetLanguage(code string) error {
+	if _, ok := catalogs[code]; !ok {
+		return fmt.Errorf("unknown language %q (available: %s)", code, strings.Join(Languages(), ", "))
+	}
+	language = code
+	return nil
+}
+
+// DetectLanguage picks a language from the POSIX locale environment variables,
+// e.g. LANG=nb_NO.UTF-8 selects "nb". Norwegian "no" and "nn" map to "nb".
+// It returns DefaultLanguage when no translation matches.
+func DetectLanguage() string {
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		v := os.Getenv(env)
+		if v == "" {
+			continue
+		}
+		code := strings.ToLower(v)
+		if i := strings.IndexAny(code, "_.@-"); i >= 0 {
+			code = code[:i]
+		}
+		if code == "no" || code == "nn" {
+			code = "nb"
+		}
+		if _, ok := catalogs[code]; ok {
+			return code
+		}
+		return DefaultLanguage
+	}
+	return DefaultLanguage
+}
+
+// tr returns the message for id in the active language, falling back to the
+// default language and then to the ID itself.
+func tr(id string) string {
+	if s, ok := catalogs[language][id]; ok {
+		return s
+	}
+	if s, ok := catalogs[DefaultLanguage][id]; ok {
+		return s
+	}
+	return id
+}
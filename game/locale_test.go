@@ -0,0 +1,76 @@
+package game
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCatalogsAreComplete(t *testing.T) {
+	for code, cat := range catalogs {
+		for id, en := range catalogs[DefaultLanguage] {
+			s, ok := cat[id]
+			if !ok {
+				t.Errorf("%s: missing message %q", code, id)
+				continue
+			}
+			if strings.Count(s, "%") != strings.Count(en, "%") {
+				t.Errorf("%s: message %q has different format verbs than English", code, id)
+			}
+		}
+	}
+}
+
+func TestCatalogsHaveGlyphs(t *testing.T) {
+	for code, cat := range catalogs {
+		for id, s := range cat {
+			for _, ch := range s {
+				if _, ok := fontGlyphs[ch]; !ok && ch != '\n' {
+					t.Errorf("%s: message %q uses %q which has no glyph", code, id, ch)
+				}
+			}
+		}
+	}
+}
+
+func TestTranslations(t *testing.T) {
+	defer SetLanguage(DefaultLanguage)
+	if err := SetLanguage("nb"); err != nil {
+		t.Fatal(err)
+	}
+	if got := tr(msgReady); got != "KLAR!" {
+		t.Errorf("nb ready: got %q, want KLAR!", got)
+	}
+	if err := SetLanguage("de"); err != nil {
+		t.Fatal(err)
+	}
+	if got := tr(msgGameOver); got != "SPIEL VORBEI" {
+		t.Errorf("de game over: got %q, want SPIEL VORBEI", got)
+	}
+	if got := tr("no_such_message"); got != "no_such_message" {
+		t.Errorf("unknown IDs should fall back to the ID, got %q", got)
+	}
+	if err := SetLanguage("xx"); err == nil {
+		t.Error("unknown language should return an error")
+	}
+}
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		lang string
+		want string
+	}{
+		{"nb_NO.UTF-8", "nb"},
+		{"no_NO", "nb"},
+		{"de_DE.UTF-8", "de"},
+		{"fr_FR.UTF-8", DefaultLanguage},
+		{"C", DefaultLanguage},
+	}
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	for _, tt := range tests {
+		t.Setenv("LANG", tt.lang)
+		if got := DetectLanguage(); got != tt.want {
+			t.Errorf("LANG=%s: got %q, want %q", tt.lang, got, tt.want)
+		}
+	}
+}
@@ -8,16 +8,44 @@ const (
 	TileEmpty
 	TileGhostHouse
 	TileGhostDoor
+	TileTunnel // empty, slows ghosts, and on the board's edge leads to the opposite edge
+	TilePortal // moving onto it comes out of its pair
+	TileOneWay // entered and left only in its direction
 )
 
-// Spawn position constants.
+// Spawn positions of the classic maze.
 const (
 	PacmanSpawnX, PacmanSpawnY           = 14, 23
 	GhostHouseCenterX, GhostHouseCenterY = 14, 14
+	FruitX, FruitY                       = 14, 17
 )
 
+// TilePos is a tile position on the board.
+type TilePos struct{ X, Y int }
+
+// Spawns are the places on a board that its tiles don't show: where Pac-Man
+// and the ghosts start, where the fruit appears, and the tiles the ghosts
+// head for in scatter mode. Blinky starts just above the ghost house door,
+// where ghosts leave and re-enter the house; eaten ghosts revive at Inky's
+// spawn.
+type Spawns struct {
+	PacMan  TilePos
+	Fruit   TilePos
+	Ghosts  [4]TilePos // by GhostID
+	Scatter [4]TilePos // may be outside the board
+}
+
+// classicSpawns are the classic maze's spawns, also used by generated mazes.
+var classicSpawns = Spawns{
+	PacMan:  TilePos{PacmanSpawnX, PacmanSpawnY},
+	Fruit:   TilePos{FruitX, FruitY},
+	Ghosts:  [4]TilePos{{14, 11}, {12, 14}, {GhostHouseCenterX, GhostHouseCenterY}, {16, 14}},
+	Scatter: [4]TilePos{{25, 0}, {2, 0}, {27, 30}, {0, 30}},
+}
+
 // mazeLayout defines the classic Pac-Man maze as a 28x31 character grid.
-// Characters: '#'=wall, '.'=dot, 'o'=power pellet, '-'=ghost door, 'G'=ghost house, ' '=empty
+// Characters: '#'=wall, '.'=dot, 'o'=power pellet, '-'=ghost door, 'G'=ghost house, ' '=empty,
+// '='=tunnel, '0'-'9'=portals (each digit a pair), '<', '>', '^', 'v'=one-way doors
 var mazeLayout = []string{
 	"############################", // row 0
 	"#............##............#", // row 1
@@ -33,7 +61,7 @@ var mazeLayout = []string{
 	"     #.##          ##.#     ", // row 11
 	"     #.## ###--### ##.#     ", // row 12
 	"######.## #GGGGGG# ##.######", // row 13
-	"      .   #GGGGGG#   .      ", // row 14
+	"======.   #GGGGGG#   .======", // row 14
 	"######.## #GGGGGG# ##.######", // row 15
 	"     #.## ######## ##.#     ", // row 16
 	"     #.##          ##.#     ", // row 17
@@ -56,27 +84,57 @@ var mazeLayout = []string{
 type Maze struct {
 	Width         int
 	Height        int
+	layout        []string // the full maze, in mazeLayout's format
+	Spawns        Spawns
 	tiles         [][]int
+	portals       map[TilePos]TilePos   // each portal's pair
+	oneWay        map[TilePos]Direction // each one-way door's direction
 	remainingDots int
+	totalDots     int
 }
 
-// NewMaze creates a new Maze by parsing the layout.
+// NewMaze creates the classic maze.
 func NewMaze() *Maze {
+	return NewMazeFromLayout(mazeLayout)
+}
+
+// NewMazeFromLayout creates a maze from rows of equal length in mazeLayout's
+// format, such as a board from GenerateMaze, with the classic maze's spawns.
+// The maze is as wide and tall as the layout.
+func NewMazeFromLayout(layout []string) *Maze {
+	return newMaze(layout, classicSpawns)
+}
+
+func newMaze(layout []string, spawns Spawns) *Maze {
 	m := &Maze{
-		Width:  MazeCols,
-		Height: MazeRows,
+		Width:  len(layout[0]),
+		Height: len(layout),
+		layout: layout,
+		Spawns: spawns,
 	}
 	m.parse()
 	return m
 }
 
-// parse reads the mazeLayout and populates the tiles grid and dot count.
+// Clone returns a full copy of the maze's board, with every dot back.
+func (m *Maze) Clone() *Maze {
+	return newMaze(m.layout, m.Spawns)
+}
+
+// oneWayChars maps the one-way door characters to their directions.
+var oneWayChars = map[byte]Direction{'^': DirUp, 'v': DirDown, '<': DirLeft, '>': DirRight}
+
+// parse reads the layout and populates the tiles grid, portals, one-way
+// doors and dot count.
 func (m *Maze) parse() {
 	m.tiles = make([][]int, m.Height)
+	m.portals = map[TilePos]TilePos{}
+	m.oneWay = map[TilePos]Direction{}
+	var pairs [10][]TilePos
 	m.remainingDots = 0
 	for y := 0; y < m.Height; y++ {
 		m.tiles[y] = make([]int, m.Width)
-		row := mazeLayout[y]
+		row := m.layout[y]
 		for x := 0; x < m.Width; x++ {
 			var ch byte
 			if x < len(row) {
@@ -97,6 +155,14 @@ func (m *Maze) parse() {
 				m.tiles[y][x] = TileGhostDoor
 			case 'G':
 				m.tiles[y][x] = TileGhostHouse
+			case '=':
+				m.tiles[y][x] = TileTunnel
+			case '^', 'v', '<', '>':
+				m.tiles[y][x] = TileOneWay
+				m.oneWay[TilePos{x, y}] = oneWayChars[ch]
+			case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+				m.tiles[y][x] = TilePortal
+				pairs[ch-'0'] = append(pairs[ch-'0'], TilePos{x, y})
 			case ' ':
 				m.tiles[y][x] = TileEmpty
 			default:
@@ -104,28 +170,79 @@ func (m *Maze) parse() {
 			}
 		}
 	}
+	// A portal without exactly one other of its digit leads nowhere, see
+	// Validate
+	for _, pair := range pairs {
+		if len(pair) == 2 {
+			m.portals[pair[0]], m.portals[pair[1]] = pair[1], pair[0]
+		}
+	}
+	m.totalDots = m.remainingDots
 }
 
-// TileAt returns the tile type at the given grid position.
-// Wraps x horizontally for tunnel support. Returns TileWall for out-of-bounds y.
+// TileAt returns the tile type at the given grid position, or TileWall off
+// the board.
 func (m *Maze) TileAt(x, y int) int {
-	if y < 0 || y >= m.Height {
+	if x < 0 || x >= m.Width || y < 0 || y >= m.Height {
 		return TileWall
 	}
-	// Horizontal wrapping for tunnel
-	if x < 0 {
-		x += m.Width
-	} else if x >= m.Width {
-		x -= m.Width
-	}
 	return m.tiles[y][x]
 }
 
+// IsTunnel reports whether (x, y) is a tunnel tile, where ghosts slow down.
+func (m *Maze) IsTunnel(x, y int) bool {
+	return m.TileAt(x, y) == TileTunnel
+}
+
+// Step returns the tile one move from (x, y) in direction d. Moving off the
+// board from a tunnel tile comes back on at the opposite edge, and moving
+// onto a portal comes out of its pair; moving off the board anywhere else
+// gives a tile off the board, which TileAt reports as a wall.
+func (m *Maze) Step(x, y int, d Direction) (int, int) {
+	nx, ny := nextTile(x, y, d)
+	if nx < 0 || nx >= m.Width || ny < 0 || ny >= m.Height {
+		if m.TileAt(x, y) != TileTunnel {
+			return nx, ny
+		}
+		nx, ny = wrapIndex(nx, m.Width), wrapIndex(ny, m.Height)
+	}
+	if p, ok := m.portals[TilePos{nx, ny}]; ok {
+		return p.X, p.Y
+	}
+	return nx, ny
+}
+
+// CanMove reports whether Pac-Man can move from (x, y) in direction d, and
+// the tile reached, following Step.
+func (m *Maze) CanMove(x, y int, d Direction) (nx, ny int, ok bool) {
+	return m.move(x, y, d, m.IsPassable)
+}
+
+// CanGhostMove reports whether an active ghost can move from (x, y) in
+// direction d, and the tile reached, following Step.
+func (m *Maze) CanGhostMove(x, y int, d Direction) (nx, ny int, ok bool) {
+	return m.move(x, y, d, m.IsPassableForGhost)
+}
+
+// move steps from (x, y) in direction d onto a tile that pass allows,
+// leaving and entering one-way doors only in their direction.
+func (m *Maze) move(x, y int, d Direction, pass func(x, y int) bool) (nx, ny int, ok bool) {
+	nx, ny = m.Step(x, y, d)
+	return nx, ny, pass(nx, ny) && m.oneWayAllows(x, y, d) && m.oneWayAllows(nx, ny, d)
+}
+
+// oneWayAllows reports whether moving in direction d is allowed on (x, y):
+// anywhere but a one-way door pointing another way.
+func (m *Maze) oneWayAllows(x, y int, d Direction) bool {
+	dir, ok := m.oneWay[TilePos{x, y}]
+	return !ok || dir == d
+}
+
 // IsPassable returns true if Pac-Man can move through the tile at (x, y).
-// Dots, power pellets, and empty tiles are passable. Walls, ghost house, and ghost doors are not.
+// Walls, ghost house, and ghost doors are not passable; every other tile is.
 func (m *Maze) IsPassable(x, y int) bool {
 	t := m.TileAt(x, y)
-	return t == TileDot || t == TilePowerPellet || t == TileEmpty
+	return t != TileWall && t != TileGhostDoor && t != TileGhostHouse
 }
 
 // IsPassableForGhost returns true if a ghost can move through the tile at (x, y).
@@ -155,6 +272,11 @@ func (m *Maze) RemainingDots() int {
 	return m.remainingDots
 }
 
+// TotalDots returns the number of dots and power pellets in a full maze.
+func (m *Maze) TotalDots() int {
+	return m.totalDots
+}
+
 // Reset re-parses the layout to restore all dots and power pellets.
 func (m *Maze) Reset() {
 	m.parse()
@@ -61,3 +61,67 @@ func TestMazeReset(t *testing.T) {
 		t.Error("reset should restore all dots")
 	}
 }
+
+func TestMazeTunnels(t *testing.T) {
+	m := NewMaze()
+	tests := []struct {
+		x, y int
+		want bool
+	}{
+		{0, 14, true},
+		{5, 14, true},
+		{6, 14, false}, // the corridor beside the tunnel
+		{22, 14, true},
+		{27, 14, true},
+		{-1, 14, false}, // off the board
+		{21, 14, false},
+		{0, 11, false}, // outside the maze, but not between walls
+		{1, 1, false},
+	}
+	for _, tt := range tests {
+		if got := m.IsTunnel(tt.x, tt.y); got != tt.want {
+			t.Errorf("IsTunnel(%d, %d): got %v, want %v", tt.x, tt.y, got, tt.want)
+		}
+	}
+}
+
+// portalMaze has a vertical tunnel through column 3, a pair of portals on
+// row 1 and one-way doors on row 2.
+var portalMaze = []string{
+	"###=###",
+	"#1...1#",
+	"#.>.<.#",
+	"###=###",
+}
+
+func TestMazeStep(t *testing.T) {
+	m := NewMazeFromLayout(portalMaze)
+	tests := []struct {
+		name   string
+		x, y   int
+		dir    Direction
+		nx, ny int
+		ok     bool
+	}{
+		{"into the tunnel", 3, 1, DirUp, 3, 0, true},
+		{"up off the board", 3, 0, DirUp, 3, 3, true},
+		{"down off the board", 3, 3, DirDown, 3, 0, true},
+		{"into a portal", 2, 1, DirLeft, 5, 1, true},
+		{"into the other portal", 4, 1, DirRight, 1, 1, true},
+		{"onto a one-way door its way", 1, 2, DirRight, 2, 2, true},
+		{"onto a one-way door against it", 3, 2, DirLeft, 2, 2, false},
+		{"off a one-way door its way", 2, 2, DirRight, 3, 2, true},
+		{"off a one-way door sideways", 2, 2, DirUp, 2, 1, false},
+		{"into a wall", 1, 2, DirLeft, 0, 2, false},
+	}
+	for _, tt := range tests {
+		nx, ny, ok := m.CanMove(tt.x, tt.y, tt.dir)
+		if nx != tt.nx || ny != tt.ny || ok != tt.ok {
+			t.Errorf("%s: CanMove(%d, %d, %v) = %d, %d, %v; want %d, %d, %v", tt.name, tt.x, tt.y, tt.dir, nx, ny, ok, tt.nx, tt.ny, tt.ok)
+		}
+	}
+	// Only tunnels lead off the board
+	if nx, ny := NewMazeFromLayout([]string{"#####", ".....", "#####"}).Step(0, 1, DirLeft); nx != -1 || ny != 1 {
+		t.Errorf("got %d, %d stepping off the board from an empty tile, want -1, 1", nx, ny)
+	}
+}
@@ -0,0 +1,360 @@
+package game
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// mazeFileHeader is the first line of a maze file. Version 1 files, from
+// before tunnel tiles, are still read; see ParseMazeFile.
+const (
+	mazeFileHeader   = "go-pacman maze 2"
+	mazeFileHeaderV1 = "go-pacman maze 1"
+)
+
+// mazeFileGhosts names the ghosts in maze files, by GhostID.
+var mazeFileGhosts = []string{"blinky", "pinky", "inky", "clyde"}
+
+// WriteMazeFile writes m in the maze file format:
+//
+//	go-pacman maze 2
+//	pacman 14 23
+//	fruit 14 17
+//	blinky 14 11 25 0
+//	pinky 12 14 2 0
+//	inky 14 14 27 30
+//	clyde 16 14 0 30
+//	layout
+//	############################
+//	#............##............#
+//	...
+//
+// Spawns are tile columns and rows; each ghost's line gives its spawn, then
+// its scatter target. After "layout" come the board's rows in mazeLayout's
+// format.
+func WriteMazeFile(w io.Writer, m *Maze) error {
+	bw := bufio.NewWriter(w)
+	s := m.Spawns
+	fmt.Fprintln(bw, mazeFileHeader)
+	fmt.Fprintf(bw, "pacman %d %d\nfruit %d %d\n", s.PacMan.X, s.PacMan.Y, s.Fruit.X, s.Fruit.Y)
+	for id, name := range mazeFileGhosts {
+		fmt.Fprintf(bw, "%s %d %d %d %d\n", name, s.Ghosts[id].X, s.Ghosts[id].Y, s.Scatter[id].X, s.Scatter[id].Y)
+	}
+	fmt.Fprintln(bw, "layout")
+	for _, row := range m.layout {
+		fmt.Fprintf(bw, "%-*s\n", m.Width, row)
+	}
+	return bw.Flush()
+}
+
+// ParseMazeFile reads a maze written by WriteMazeFile. The board may be any
+// size: it is as tall as its rows and as wide as the longest, with shorter
+// rows padded with empty tiles. Spawns missing from the file are the classic
+// maze's. In version 1 files, where every open tile on the left and right
+// edges led across the board, the empty tiles of the side tunnels are read as
+// tunnel tiles. The board is not checked to be playable; see Maze.Validate.
+func ParseMazeFile(rd io.Reader) (*Maze, error) {
+	sc := bufio.NewScanner(rd)
+	var header string
+	if sc.Scan() {
+		header = strings.TrimSpace(sc.Text())
+	}
+	if header != mazeFileHeader && header != mazeFileHeaderV1 {
+		if err := sc.Err(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("line 1: not a maze, expected %q", mazeFileHeader)
+	}
+	spawns := classicSpawns
+	line := 2
+	for ; sc.Scan(); line++ {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if fields[0] == "layout" {
+			break
+		}
+		if err := parseSpawn(&spawns, fields); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+
+	var layout []string
+	for line++; sc.Scan(); line++ {
+		row := strings.TrimRight(sc.Text(), "\r")
+		if i := strings.IndexFunc(row, func(r rune) bool { return !strings.ContainsRune(mazeTileChars, r) }); i >= 0 {
+			return nil, fmt.Errorf("line %d: unknown tile %q", line, row[i])
+		}
+		layout = append(layout, row)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	width := 0
+	for _, row := range layout {
+		width = max(width, len(row))
+	}
+	if width == 0 {
+		return nil, errors.New("the layout is empty")
+	}
+	for y, row := range layout {
+		layout[y] = row + strings.Repeat(" ", width-len(row))
+	}
+	if header == mazeFileHeaderV1 {
+		markSideTunnels(layout)
+	}
+	return newMaze(layout, spawns), nil
+}
+
+// mazeTileChars are the characters of a layout, as parse reads them.
+const mazeTileChars = "#.o -G=<>^v0123456789"
+
+// markSideTunnels turns the empty tiles of the side tunnels into tunnel
+// tiles: those running in from the left and right edges between walls above
+// and below, which version 1 mazes slowed ghosts in and wrapped across.
+func markSideTunnels(layout []string) {
+	wall := func(x, y int) bool { return y < 0 || y >= len(layout) || layout[y][x] == '#' }
+	for y, row := range layout {
+		b := []byte(row)
+		inTunnel := func(x int) bool {
+			return !strings.ContainsRune("#-G", rune(b[x])) && wall(x, y-1) && wall(x, y+1)
+		}
+		for x := 0; x < len(b) && inTunnel(x); x++ {
+			if b[x] == ' ' {
+				b[x] = '='
+			}
+		}
+		for x := len(b) - 1; x >= 0 && inTunnel(x); x-- {
+			if b[x] == ' ' {
+				b[x] = '='
+			}
+		}
+		layout[y] = string(b)
+	}
+}
+
+// parseSpawn reads one "pacman X Y", "fruit X Y" or "<ghost> X Y SX SY" line.
+func parseSpawn(s *Spawns, fields []string) error {
+	n := make([]int, len(fields)-1)
+	for i, f := range fields[1:] {
+		v, err := strconv.Atoi(f)
+		if err != nil {
+			return fmt.Errorf("not a number: %s", f)
+		}
+		n[i] = v
+	}
+	want := 2
+	if fields[0] != "pacman" && fields[0] != "fruit" {
+		want = 4
+	}
+	if len(n) != want {
+		return fmt.Errorf("%s takes %d numbers, got %d", fields[0], want, len(n))
+	}
+	switch fields[0] {
+	case "pacman":
+		s.PacMan = TilePos{n[0], n[1]}
+		return nil
+	case "fruit":
+		s.Fruit = TilePos{n[0], n[1]}
+		return nil
+	}
+	for id, name := range mazeFileGhosts {
+		if name == fields[0] {
+			s.Ghosts[id] = TilePos{n[0], n[1]}
+			s.Scatter[id] = TilePos{n[2], n[3]}
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown keyword %q", fields[0])
+}
+
+// LoadMazeFile reads and validates a maze file.
+func LoadMazeFile(path string) (*Maze, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("maze: %w", err)
+	}
+	defer f.Close()
+	m, err := ParseMazeFile(f)
+	if err == nil {
+		err = m.Validate()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("maze %s: %w", path, err)
+	}
+	return m, nil
+}
+
+// SaveMazeFile writes a maze file.
+func SaveMazeFile(path string, m *Maze) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("maze: %w", err)
+	}
+	if err := WriteMazeFile(f, m); err != nil {
+		f.Close()
+		return fmt.Errorf("maze %s: %w", path, err)
+	}
+	return f.Close()
+}
+
+// Hash identifies the board's layout and spawns, as the first 12 hex digits
+// of the SHA-256 of its maze file. Replays played on a custom board record it.
+func (m *Maze) Hash() string {
+	h := sha256.New()
+	WriteMazeFile(h, m) // writing to a hash cannot fail
+	return hex.EncodeToString(h.Sum(nil)[:6])
+}
+
+// Validate reports every reason the board cannot be played: Pac-Man or the
+// fruit off the open tiles, no dots or dots Pac-Man cannot reach, a ghost
+// house that ghosts cannot leave through a door, one-way doors into a pocket
+// Pac-Man or the ghosts cannot get back out of, a portal without a pair or
+// side by side with it, or a one-way door leading into a wall.
+func (m *Maze) Validate() error {
+	var errs []error
+	s := m.Spawns
+	inside := func(p TilePos) bool { return p.X >= 0 && p.X < m.Width && p.Y >= 0 && p.Y < m.Height }
+
+	if !inside(s.PacMan) || !m.IsPassable(s.PacMan.X, s.PacMan.Y) {
+		errs = append(errs, fmt.Errorf("pac-man's spawn %d,%d is not on an open tile", s.PacMan.X, s.PacMan.Y))
+	}
+	if !inside(s.Fruit) || !m.IsPassable(s.Fruit.X, s.Fruit.Y) {
+		errs = append(errs, fmt.Errorf("the fruit at %d,%d is not on an open tile", s.Fruit.X, s.Fruit.Y))
+	}
+	if m.TotalDots() == 0 {
+		errs = append(errs, errors.New("there are no dots"))
+	} else if inside(s.PacMan) {
+		reach := m.reachable(s.PacMan, m.IsPassable)
+	dots:
+		for y := 0; y < m.Height; y++ {
+			for x := 0; x < m.Width; x++ {
+				if t := m.tiles[y][x]; (t == TileDot || t == TilePowerPellet) && !reach[y][x] {
+					errs = append(errs, fmt.Errorf("pac-man cannot reach the dot at %d,%d", x, y))
+					break dots // one is enough
+				}
+			}
+		}
+		if p, ok := m.trap(s.PacMan, m.IsPassable); ok {
+			errs = append(errs, fmt.Errorf("pac-man can be trapped at %d,%d, with no way back through the one-way doors", p.X, p.Y))
+		}
+	}
+
+	exit := s.Ghosts[Blinky]
+	switch {
+	case !inside(exit) || !m.IsPassableForGhost(exit.X, exit.Y):
+		errs = append(errs, fmt.Errorf("blinky's spawn %d,%d, the ghost house exit, is not on an open tile", exit.X, exit.Y))
+	case m.TileAt(exit.X, exit.Y+1) != TileGhostDoor:
+		errs = append(errs, fmt.Errorf("blinky's spawn %d,%d, the ghost house exit, is not just above a ghost door", exit.X, exit.Y))
+	case inside(s.PacMan) && !m.reachable(exit, m.IsPassableForGhost)[s.PacMan.Y][s.PacMan.X]:
+		errs = append(errs, errors.New("ghosts leaving the house cannot reach pac-man"))
+	default:
+		if p, ok := m.trap(exit, m.IsPassableForGhost); ok {
+			errs = append(errs, fmt.Errorf("ghosts can be trapped at %d,%d, with no way back through the one-way doors", p.X, p.Y))
+		}
+	}
+	for id := Pinky; id <= Clyde; id++ {
+		if p := s.Ghosts[id]; !inside(p) || m.TileAt(p.X, p.Y) != TileGhostHouse {
+			errs = append(errs, fmt.Errorf("%s's spawn %d,%d is not in the ghost house", mazeFileGhosts[id], p.X, p.Y))
+		}
+	}
+
+	portals := map[byte]int{}
+	for y, row := range m.layout {
+		for x := range len(row) {
+			switch ch := row[x]; {
+			case m.tiles[y][x] == TilePortal:
+				portals[ch]++
+				// Stepping onto either tile would come out on the other,
+				// and straight back; report the pair once, from its first
+				if p, ok := m.portals[TilePos{x, y}]; ok && (p == TilePos{x + 1, y} || p == TilePos{x, y + 1}) {
+					errs = append(errs, fmt.Errorf("portal %c's tiles at %d,%d and %d,%d are side by side", ch, x, y, p.X, p.Y))
+				}
+			case m.tiles[y][x] == TileOneWay:
+				if _, _, ok := m.CanMove(x, y, oneWayChars[ch]); !ok {
+					errs = append(errs, fmt.Errorf("the one-way door at %d,%d leads into a wall", x, y))
+				}
+			}
+		}
+	}
+	for ch := byte('0'); ch <= '9'; ch++ {
+		if n := portals[ch]; n != 0 && n != 2 {
+			errs = append(errs, fmt.Errorf("portal %c has %d tiles, not a pair", ch, n))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// trap returns the first tile, row by row, that can be reached from start
+// through tiles that pass but has no way back to it, and whether there is
+// one. Only one-way doors make such a tile.
+func (m *Maze) trap(start TilePos, pass func(x, y int) bool) (TilePos, bool) {
+	// Search back from start along every move, reversed
+	from := map[TilePos][]TilePos{}
+	for y := 0; y < m.Height; y++ {
+		for x := 0; x < m.Width; x++ {
+			if !pass(x, y) {
+				continue
+			}
+			for _, d := range []Direction{DirUp, DirLeft, DirDown, DirRight} {
+				if nx, ny, ok := m.move(x, y, d, pass); ok {
+					to := TilePos{nx, ny}
+					from[to] = append(from[to], TilePos{x, y})
+				}
+			}
+		}
+	}
+	back := map[TilePos]bool{start: true}
+	queue := []TilePos{start}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, p := range from[cur] {
+			if !back[p] {
+				back[p] = true
+				queue = append(queue, p)
+			}
+		}
+	}
+
+	reach := m.reachable(start, pass)
+	for y := 0; y < m.Height; y++ {
+		for x := 0; x < m.Width; x++ {
+			if reach[y][x] && !back[TilePos{x, y}] {
+				return TilePos{x, y}, true
+			}
+		}
+	}
+	return TilePos{}, false
+}
+
+// reachable returns the tiles reachable from start through tiles that pass,
+// through tunnels and portals and along one-way doors.
+func (m *Maze) reachable(start TilePos, pass func(x, y int) bool) [][]bool {
+	seen := make([][]bool, m.Height)
+	for y := range seen {
+		seen[y] = make([]bool, m.Width)
+	}
+	seen[start.Y][start.X] = true
+	queue := []TilePos{start}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, d := range []Direction{DirUp, DirLeft, DirDown, DirRight} {
+			nx, ny, ok := m.move(cur.X, cur.Y, d, pass)
+			if ok && !seen[ny][nx] {
+				seen[ny][nx] = true
+				queue = append(queue, TilePos{nx, ny})
+			}
+		}
+	}
+	return seen
+}
@@ -0,0 +1,155 @@
+package game
+
+import (
+	"bytes"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMazeFileRoundTrip(t *testing.T) {
+	m := NewMaze()
+	m.Spawns.Fruit = TilePos{13, 17}
+	m.Spawns.Scatter[Clyde] = TilePos{1, 29}
+	var buf bytes.Buffer
+	if err := WriteMazeFile(&buf, m); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ParseMazeFile(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.layout, m.layout) || got.Spawns != m.Spawns {
+		t.Errorf("got spawns %+v, want %+v", got.Spawns, m.Spawns)
+	}
+}
+
+func TestSaveAndLoadMazeFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "maze.txt")
+	if err := SaveMazeFile(path, NewMaze()); err != nil {
+		t.Fatal(err)
+	}
+	m, err := LoadMazeFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.TotalDots() != NewMaze().TotalDots() {
+		t.Errorf("got %d dots, want %d", m.TotalDots(), NewMaze().TotalDots())
+	}
+}
+
+func TestParseMazeFileErrors(t *testing.T) {
+	tests := []struct {
+		src, want string
+	}{
+		{"a maze\n", "line 1:"},
+		{mazeFileHeader + "\npacman 1\n", "line 2:"},
+		{mazeFileHeader + "\nfruit 1 x\n", "line 2:"},
+		{mazeFileHeader + "\n\nwarp 1 2\n", "line 3:"},
+		{mazeFileHeader + "\nlayout\n#?#\n", "line 3:"},
+		{mazeFileHeader + "\nlayout\n", "the layout is empty"},
+		{mazeFileHeader + "\npacman 1 1\n", "the layout is empty"},
+	}
+	for _, tt := range tests {
+		_, err := ParseMazeFile(strings.NewReader(tt.src))
+		if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+			t.Errorf("%q: got error %v, want one starting %q", tt.src, err, tt.want)
+		}
+	}
+}
+
+func TestParseMazeFilePadsRows(t *testing.T) {
+	var src strings.Builder
+	src.WriteString(mazeFileHeader + "\nlayout\n")
+	for _, row := range mazeLayout {
+		src.WriteString(strings.TrimRight(row, " ") + "\n")
+	}
+	m, err := ParseMazeFile(strings.NewReader(src.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(m.layout, mazeLayout) || m.Spawns != classicSpawns {
+		t.Error("trimmed rows should read back as the classic maze")
+	}
+}
+
+func TestParseMazeFileAnySize(t *testing.T) {
+	src := mazeFileHeader + `
+pacman 1 3
+fruit 3 3
+blinky 3 1 0 0
+pinky 3 2 0 0
+inky 3 2 6 4
+clyde 3 2 6 4
+layout
+#######
+#.. ..#
+#.#-#.#
+.#GGG#.
+#.....#
+#######
+`
+	m, err := ParseMazeFile(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Width != 7 || m.Height != 6 {
+		t.Fatalf("got a %dx%d maze, want 7x6", m.Width, m.Height)
+	}
+}
+
+func TestParseMazeFileV1(t *testing.T) {
+	var src strings.Builder
+	src.WriteString(mazeFileHeaderV1 + "\nlayout\n")
+	for _, row := range mazeLayout {
+		src.WriteString(strings.ReplaceAll(row, "=", " ") + "\n")
+	}
+	m, err := ParseMazeFile(strings.NewReader(src.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(m.layout, mazeLayout) {
+		t.Errorf("a version 1 classic maze should read back with its side tunnels, got row 14 %q", m.layout[14])
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := NewMaze().Validate(); err != nil {
+		t.Fatalf("the classic maze should be valid: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		edit func(layout [][]byte, s *Spawns)
+		want string
+	}{
+		{"pac-man in a wall", func(l [][]byte, s *Spawns) { s.PacMan = TilePos{0, 0} }, "pac-man's spawn"},
+		{"fruit off the board", func(l [][]byte, s *Spawns) { s.Fruit = TilePos{-1, 3} }, "the fruit"},
+		{"walled-in dot", func(l [][]byte, s *Spawns) { l[1][2], l[2][1] = '#', '#' }, "pac-man cannot reach the dot at 1,1"},
+		{"no door", func(l [][]byte, s *Spawns) { l[12][13], l[12][14] = '#', '#' }, "not just above a ghost door"},
+		{"pinky outside", func(l [][]byte, s *Spawns) { s.Ghosts[Pinky] = TilePos{1, 1} }, "pinky's spawn"},
+		{"unpaired portal", func(l [][]byte, s *Spawns) { l[1][1] = '3' }, "portal 3 has 1 tiles"},
+		{"one-way door into a wall", func(l [][]byte, s *Spawns) { l[1][1] = '^' }, "the one-way door at 1,1 leads into a wall"},
+		{"one-way pocket", func(l [][]byte, s *Spawns) { l[1][2], l[2][1] = '<', '#' }, "pac-man can be trapped at 1,1"},
+		{"one-way pocket for ghosts", func(l [][]byte, s *Spawns) { l[1][2], l[2][1] = '<', '#' }, "ghosts can be trapped at 1,1"},
+		{"portals side by side", func(l [][]byte, s *Spawns) { l[1][1], l[1][2] = '5', '5' }, "portal 5's tiles at 1,1 and 2,1 are side by side"},
+		{"portals one above the other", func(l [][]byte, s *Spawns) { l[1][1], l[2][1] = '5', '5' }, "portal 5's tiles at 1,1 and 1,2 are side by side"},
+	}
+	for _, tt := range tests {
+		layout := make([][]byte, len(mazeLayout))
+		for y, row := range mazeLayout {
+			layout[y] = []byte(row)
+		}
+		s := classicSpawns
+		tt.edit(layout, &s)
+		rows := make([]string, len(layout))
+		for y, row := range layout {
+			rows[y] = string(row)
+		}
+		err := newMaze(rows, s).Validate()
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: got error %v, want one containing %q", tt.name, err, tt.want)
+		}
+	}
+}
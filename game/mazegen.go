@@ -0,0 +1,212 @@
+package game
+
+import "math/rand"
+
+// The generator lays corridors along lines of the left half of the board and
+// mirrors them. Lines are the arcade maze's own, at least three tiles apart,
+// so corridors are one tile wide with walls at least two tiles thick between
+// them. Where the lines cross are nodes; a corridor joins two neighbouring
+// nodes, or a node on the last column to its mirror image across the middle.
+var (
+	genCols = []int{1, 6, 9, 12} // mirrored to 26, 21, 18, 15
+	genRows = []int{1, 5, 8, 11, 14, 17, 20, 23, 26, 29}
+)
+
+// genNode is a node by its column and row index in genCols and genRows.
+type genNode struct{ c, r int }
+
+// genEdge is a corridor between two nodes. A corridor to the mirror image
+// crosses the middle and has b.c == len(genCols).
+type genEdge struct{ a, b genNode }
+
+func (e genEdge) crossesMiddle() bool {
+	return e.b.c == len(genCols)
+}
+
+// Nodes and corridors the generator keeps clear of or always keeps, so that
+// the ghost house, its surrounding ring, the side tunnels and the fixed
+// spawns are the same on every board.
+var (
+	genTunnelNode = genNode{0, 4} // row 14, where the side tunnels run in
+	genHouseNode  = genNode{3, 4} // inside the ghost house wall
+	genFixed      = []genEdge{
+		{genNode{2, 3}, genNode{3, 3}}, {genNode{3, 3}, genNode{4, 3}}, // above the house, with the door
+		{genNode{2, 5}, genNode{3, 5}}, {genNode{3, 5}, genNode{4, 5}}, // below the house, with the fruit
+		{genNode{2, 3}, genNode{2, 4}}, {genNode{2, 4}, genNode{2, 5}}, // beside the house
+		{genNode{0, 4}, genNode{1, 4}}, // from the tunnel
+		{genNode{3, 7}, genNode{4, 7}}, // Pac-Man's spawn
+	}
+	genForbidden = []genEdge{
+		{genNode{0, 3}, genNode{0, 4}}, {genNode{0, 4}, genNode{0, 5}}, // keep the tunnel walled in
+	}
+)
+
+// genRemoveChance is how likely each removable corridor is to go. Lower keeps
+// more crossings; higher makes longer, winding corridors.
+const genRemoveChance = 0.55
+
+// GenerateMaze returns a new random board in mazeLayout's format, for
+// NewMazeFromLayout. Boards are symmetric, with the arcade maze's ghost
+// house, tunnels and spawns, four power pellets, no dead ends, and every
+// corridor reachable. The same random source state gives the same board.
+func GenerateMaze(r *rand.Rand) []string {
+	edges := genAllEdges()
+	present := make(map[genEdge]bool, len(edges))
+	for _, e := range edges {
+		present[e] = true
+	}
+	fixed := make(map[genEdge]bool, len(genFixed))
+	for _, e := range genFixed {
+		fixed[e] = true
+	}
+
+	// Take corridors away while every node keeps two ways out and the board
+	// stays in one piece
+	r.Shuffle(len(edges), func(i, j int) { edges[i], edges[j] = edges[j], edges[i] })
+	for _, e := range edges {
+		if fixed[e] || r.Float64() >= genRemoveChance {
+			continue
+		}
+		present[e] = false
+		if genDegree(present, e.a) < 2 || !e.crossesMiddle() && genDegree(present, e.b) < 2 || !genConnected(present) {
+			present[e] = true
+		}
+	}
+
+	half := make([][]byte, MazeRows)
+	for y := range half {
+		half[y] = []byte("##############")
+	}
+	for e, ok := range present {
+		if ok {
+			genCarve(half, e)
+		}
+	}
+	half[genRows[genTunnelNode.r]][0] = ' '
+
+	// Two power pellets in each half, one in the top corner and one in the
+	// bottom, on nodes of the outer columns
+	top := genNode{r.Intn(2), r.Intn(3)}
+	bottom := genNode{r.Intn(2), 6 + r.Intn(4)}
+	for _, n := range []genNode{top, bottom} {
+		half[genRows[n.r]][genCols[n.c]] = 'o'
+	}
+
+	// No dots around the ghost house, in the tunnels or under Pac-Man
+	for y := 9; y <= 19; y++ {
+		for x := 7; x < len(half[y]); x++ {
+			if half[y][x] == '.' {
+				half[y][x] = ' '
+			}
+		}
+	}
+	for x := 0; x < genCols[1]; x++ {
+		half[genRows[genTunnelNode.r]][x] = '='
+	}
+	half[PacmanSpawnY][MazeCols/2-1] = ' '
+
+	// The ghost house, as in the arcade maze
+	copy(half[12][10:], "###-")
+	for y := 13; y <= 15; y++ {
+		copy(half[y][10:], "#GGG")
+	}
+	copy(half[16][10:], "####")
+
+	layout := make([]string, MazeRows)
+	for y, row := range half {
+		full := make([]byte, MazeCols)
+		for x, ch := range row {
+			full[x] = ch
+			full[MazeCols-1-x] = ch
+		}
+		layout[y] = string(full)
+	}
+	return layout
+}
+
+// genAllEdges lists every corridor the generator may use, in a fixed order.
+func genAllEdges() []genEdge {
+	var edges []genEdge
+	for r := range genRows {
+		for c := range genCols {
+			n := genNode{c, r}
+			if n == genHouseNode {
+				continue
+			}
+			for _, next := range []genNode{{c + 1, r}, {c, r + 1}} {
+				if next.r == len(genRows) || next == genHouseNode {
+					continue
+				}
+				e := genEdge{n, next}
+				forbidden := false
+				for _, f := range genForbidden {
+					forbidden = forbidden || f == e
+				}
+				if !forbidden {
+					edges = append(edges, e)
+				}
+			}
+		}
+	}
+	return edges
+}
+
+// genDegree counts the ways out of a node, including the tunnel.
+func genDegree(present map[genEdge]bool, n genNode) int {
+	d := 0
+	if n == genTunnelNode {
+		d++
+	}
+	for e, ok := range present {
+		if ok && (e.a == n || e.b == n) {
+			d++
+		}
+	}
+	return d
+}
+
+// genConnected reports whether every node of the left half is reachable from
+// every other. Corridors across the middle only join a node to its own mirror
+// image, so the left half being in one piece makes the whole board one.
+func genConnected(present map[genEdge]bool) bool {
+	start := genNode{0, 0}
+	seen := map[genNode]bool{start: true}
+	queue := []genNode{start}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		for e, ok := range present {
+			if !ok || e.crossesMiddle() {
+				continue
+			}
+			var next genNode
+			switch n {
+			case e.a:
+				next = e.b
+			case e.b:
+				next = e.a
+			default:
+				continue
+			}
+			if !seen[next] {
+				seen[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return len(seen) == len(genRows)*len(genCols)-1 // all but the house node
+}
+
+// genCarve opens a corridor's tiles in the left half of the board.
+func genCarve(half [][]byte, e genEdge) {
+	x0, y0 := genCols[e.a.c], genRows[e.a.r]
+	x1, y1 := MazeCols/2-1, genRows[e.b.r]
+	if !e.crossesMiddle() {
+		x1 = genCols[e.b.c]
+	}
+	for y := y0; y <= y1; y++ {
+		for x := x0; x <= x1; x++ {
+			half[y][x] = '.'
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package game
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestGeneratedMazesAreValid(t *testing.T) {
+	for seed := int64(1); seed <= 50; seed++ {
+		layout := GenerateMaze(rand.New(rand.NewSource(seed)))
+		m := NewMazeFromLayout(layout)
+		if len(layout) != MazeRows {
+			t.Fatalf("seed %d: got %d rows, want %d", seed, len(layout), MazeRows)
+		}
+
+		pellets := 0
+		for y, row := range layout {
+			if len(row) != MazeCols {
+				t.Fatalf("seed %d: row %d has %d columns, want %d", seed, y, len(row), MazeCols)
+			}
+			for x := range row {
+				if row[x] != row[MazeCols-1-x] {
+					t.Errorf("seed %d: tile %d,%d is not mirrored", seed, x, y)
+				}
+				if row[x] == 'o' {
+					pellets++
+				}
+			}
+		}
+		if pellets != 4 {
+			t.Errorf("seed %d: got %d power pellets, want 4", seed, pellets)
+		}
+		if err := m.Validate(); err != nil {
+			t.Errorf("seed %d: %v", seed, err)
+		}
+
+		reachable := mazeReachable(m)
+		for y := 0; y < m.Height; y++ {
+			for x := 0; x < m.Width; x++ {
+				if !m.IsPassable(x, y) {
+					continue
+				}
+				if !reachable[y][x] {
+					t.Errorf("seed %d: tile %d,%d cannot be reached", seed, x, y)
+				}
+				exits := 0
+				for _, d := range []Direction{DirUp, DirLeft, DirDown, DirRight} {
+					if _, _, ok := m.CanMove(x, y, d); ok {
+						exits++
+					}
+				}
+				if exits < 2 {
+					t.Errorf("seed %d: tile %d,%d is a dead end", seed, x, y)
+				}
+				if m.IsPassable(x+1, y) && m.IsPassable(x, y+1) && m.IsPassable(x+1, y+1) {
+					t.Errorf("seed %d: corridors at %d,%d are not two tiles apart", seed, x, y)
+				}
+			}
+		}
+
+		switch {
+		case m.TileAt(13, 12) != TileGhostDoor || m.TileAt(GhostHouseCenterX, GhostHouseCenterY) != TileGhostHouse:
+			t.Errorf("seed %d: no ghost house with a door", seed)
+		case !m.IsTunnel(0, 14) || !m.IsTunnel(MazeCols-1, 14):
+			t.Errorf("seed %d: no side tunnels", seed)
+		case !m.IsPassable(PacmanSpawnX, PacmanSpawnY) || !m.IsPassable(FruitX, FruitY):
+			t.Errorf("seed %d: Pac-Man's spawn or the fruit is in a wall", seed)
+		}
+	}
+}
+
+func TestGenerateMazeIsSeeded(t *testing.T) {
+	a := GenerateMaze(rand.New(rand.NewSource(3)))
+	b := GenerateMaze(rand.New(rand.NewSource(3)))
+	c := GenerateMaze(rand.New(rand.NewSource(4)))
+	if !slices.Equal(a, b) {
+		t.Error("the same seed should give the same maze")
+	}
+	if slices.Equal(a, c) {
+		t.Error("different seeds should give different mazes")
+	}
+}
+
+func TestGeneratedMazeEachLevel(t *testing.T) {
+	rules := DefaultRules()
+	rules.Maze = MazeGenerated
+	g := New()
+	g.SetRules(rules)
+	g.startGame(5, 2)
+	first := g.maze.layout
+	if slices.Equal(first, mazeLayout) {
+		t.Fatal("the first level should have a generated maze")
+	}
+	if !slices.Equal(g.waiting.maze.layout, first) {
+		t.Error("both players should start on the same maze")
+	}
+
+	g.level = 2
+	g.startLevel()
+	if slices.Equal(g.maze.layout, first) || g.maze.RemainingDots() != g.maze.TotalDots() {
+		t.Error("the next level should have a new, full maze")
+	}
+
+	g.startGame(5, 1)
+	if !slices.Equal(g.maze.layout, first) {
+		t.Error("the same seed should give the same first maze")
+	}
+}
@@ -0,0 +1,217 @@
+package game
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Game modes, chosen on the title screen. The IDs name the modes in high
+// score files and replays.
+const (
+	ModeClassic    = "classic"    // clear the maze level after level
+	ModeTimeAttack = "timeattack" // score as much as possible before the clock runs out
+	ModeEndless    = "endless"    // the maze refills in waves instead of ending the level
+	ModeSurvival   = "survival"   // one life, scoring for time alive as ghosts speed up
+)
+
+// Modes lists the game modes in title menu order.
+var Modes = []string{ModeClassic, ModeTimeAttack, ModeEndless, ModeSurvival}
+
+// modeNames are the modes' message IDs.
+var modeNames = map[string]string{
+	ModeClassic:    msgModeClassic,
+	ModeTimeAttack: msgModeTimeAttack,
+	ModeEndless:    msgModeEndless,
+	ModeSurvival:   msgModeSurvival,
+}
+
+// Mode rules, in ticks of play at 60 TPS. The clocks stand still during
+// READY!, death and level-clear pauses.
+const (
+	timeAttackTicks   = 3 * 60 * 60 // three minutes
+	survivalScore     = 10          // points for each second alive
+	survivalStepTicks = 15 * 60     // ghosts speed up this often...
+	survivalSpeedStep = 0.05        // ...by this much of their level's speed
+)
+
+// gameMode is the part of a game's rules that differs between modes. The
+// hooks run from startGame and updatePlaying, so every mode shares the one
+// update loop; modes embed classicMode and override only what they change.
+type gameMode interface {
+	// start sets up a new game, after the classic setup.
+	start(g *Game)
+	// tick runs once per tick of play, after Pac-Man and the ghosts move,
+	// and reports whether the game is over.
+	tick(g *Game) bool
+	// mazeCleared runs when the last dot is eaten and reports whether the
+	// level is over.
+	mazeCleared(g *Game) bool
+	// ghostSpeedScale multiplies the ghosts' speeds.
+	ghostSpeedScale() float64
+	// status is shown at the top right of the HUD, or nothing if empty.
+	status() string
+}
+
+// newMode returns the rules of a mode, ready for a new game. Unknown IDs get
+// the classic rules.
+func newMode(id string) gameMode {
+	switch id {
+	case ModeTimeAttack:
+		return &timeAttackMode{}
+	case ModeEndless:
+		return &endlessMode{}
+	case ModeSurvival:
+		return &survivalMode{}
+	}
+	return classicMode{}
+}
+
+// validMode reports whether id is one of Modes.
+func validMode(id string) bool {
+	return slices.Contains(Modes, id)
+}
+
+// SetMode picks the mode of the next game, as the title menu does.
+func (g *Game) SetMode(id string) error {
+	if !validMode(id) {
+		return fmt.Errorf("unknown mode %q", id)
+	}
+	g.modeID = id
+	return nil
+}
+
+// classicMode is the usual game: each cleared maze is a level, and the game
+// lasts until the last life.
+type classicMode struct{}
+
+func (classicMode) start(g *Game)            {}
+func (classicMode) tick(g *Game) bool        { return false }
+func (classicMode) mazeCleared(g *Game) bool { return true }
+func (classicMode) ghostSpeedScale() float64 { return 1 }
+func (classicMode) status() string           { return "" }
+
+// timeAttackMode ends the game when its clock runs out.
+type timeAttackMode struct {
+	classicMode
+	ticksLeft int
+}
+
+func (m *timeAttackMode) start(g *Game) {
+	m.ticksLeft = timeAttackTicks
+}
+
+func (m *timeAttackMode) tick(g *Game) bool {
+	m.ticksLeft--
+	return m.ticksLeft <= 0
+}
+
+func (m *timeAttackMode) status() string {
+	// Rounded up, so the clock shows 0:00 only once time is up
+	return fmt.Sprintf(tr(msgTime), clock(m.ticksLeft+59))
+}
+
+// endlessMode refills the maze each time it is cleared, one level harder,
+// without stopping play.
+type endlessMode struct {
+	classicMode
+	wave int
+}
+
+func (m *endlessMode) start(g *Game) {
+	m.wave = 1
+}
+
+func (m *endlessMode) mazeCleared(g *Game) bool {
+	m.wave++
+	g.level++
+	g.refillMaze()
+	return false
+}
+
+func (m *endlessMode) status() string {
+	return fmt.Sprintf(tr(msgWave), m.wave)
+}
+
+// survivalMode gives one life and no extra lives, scores each second alive
+// and speeds the ghosts up as time goes on. Cleared mazes refill like
+// endless mode's, keeping the ghosts' speed.
+type survivalMode struct {
+	classicMode
+	ticks int // ticks of play survived
+}
+
+func (m *survivalMode) start(g *Game) {
+	m.ticks = 0
+	g.lives = 1
+	g.nextExtraLife = 0
+}
+
+func (m *survivalMode) tick(g *Game) bool {
+	m.ticks++
+	if m.ticks%60 == 0 {
+		g.score += survivalScore
+	}
+	return false
+}
+
+func (m *survivalMode) mazeCleared(g *Game) bool {
+	g.refillMaze()
+	return false
+}
+
+func (m *survivalMode) ghostSpeedScale() float64 {
+	return 1 + survivalSpeedStep*float64(m.ticks/survivalStepTicks)
+}
+
+func (m *survivalMode) status() string {
+	return fmt.Sprintf(tr(msgTime), clock(m.ticks))
+}
+
+// refillMaze puts every dot back during play, for modes whose maze never
+// runs out. Pac-Man and the ghosts carry on where they are.
+func (g *Game) refillMaze() {
+	g.maze.Reset()
+	g.clearFruit()
+	g.sound.PlayLevelClear()
+}
+
+// clock formats ticks as minutes and whole seconds.
+func clock(ticks int) string {
+	seconds := max(ticks, 0) / 60
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
+
+// Layout of the title screen's mode menu and high score table.
+const (
+	titleModesTop    = 80
+	titleModeHeight  = 12
+	titleScoresTop   = 190
+	titleScoreHeight = 10
+)
+
+// drawTitle draws the title screen: the mode menu with the chosen mode
+// highlighted, and that mode's high score table.
+func (g *Game) drawTitle(screen Canvas) {
+	centered := &TextOptions{Color: theme.Palette.Text, Align: AlignCenter}
+	ox, oy := g.menuOrigin()
+	cx := ox + MinScreenWidth/2
+	DrawTextWith(screen, tr(msgTitle), cx, oy+40, &TextOptions{Color: theme.Palette.Text, Align: AlignCenter, Scale: 2})
+	for i, id := range Modes {
+		c := theme.Palette.Text
+		if id == g.modeID {
+			c = theme.Palette.Highlight
+		}
+		DrawTextWith(screen, tr(modeNames[id]), cx, oy+titleModesTop+i*titleModeHeight, &TextOptions{Color: c, Align: AlignCenter})
+	}
+	DrawTextWith(screen, tr(msgPressStart), cx, oy+136, centered)
+	if g.modeID == ModeClassic {
+		DrawTextWith(screen, tr(msgTwoPlayers), cx, oy+160, centered)
+	}
+	DrawTextWith(screen, tr(msgHighScore), cx, oy+titleScoresTop, centered)
+	for i, score := range g.highScores[g.modeID] {
+		// The font is monospaced, so padded scores line up
+		DrawTextWith(screen, fmt.Sprintf("%d %7d", i+1, score), cx, oy+titleScoresTop+(i+1)*titleScoreHeight, centered)
+	}
+	DrawTextWith(screen, tr(msgOpenSettings), cx, oy+256, centered)
+	DrawTextWith(screen, tr(msgOpenEditor), cx, oy+268, centered)
+}
@@ -0,0 +1,122 @@
+package game
+
+import (
+	"math"
+	"testing"
+)
+
+// startMode starts a one-player game of a mode and skips READY!.
+func startMode(t *testing.T, mode string) *Game {
+	t.Helper()
+	g := New()
+	if err := g.SetMode(mode); err != nil {
+		t.Fatal(err)
+	}
+	g.startGame(1, 1)
+	g.state = StatePlaying
+	return g
+}
+
+func TestSetModeUnknown(t *testing.T) {
+	if err := New().SetMode("marathon"); err == nil {
+		t.Error("an unknown mode should be an error")
+	}
+}
+
+func TestClassicLevelClear(t *testing.T) {
+	g := startMode(t, ModeClassic)
+	eatDots(g, g.maze.TotalDots())
+	g.updatePlaying()
+	if g.state != StateLevelClear {
+		t.Errorf("got state %v, want level clear", g.state)
+	}
+	if g.hud().Status != "" {
+		t.Errorf("classic games have no status, got %q", g.hud().Status)
+	}
+}
+
+func TestTimeAttack(t *testing.T) {
+	g := startMode(t, ModeTimeAttack)
+	if got := g.hud().Status; got != "TIME\n3:00" {
+		t.Errorf("status: got %q, want TIME 3:00", got)
+	}
+	g.updatePlaying()
+	if got := g.hud().Status; got != "TIME\n3:00" {
+		t.Errorf("the clock should round up, got %q", got)
+	}
+	g.score = 250
+	g.mode.(*timeAttackMode).ticksLeft = 2
+	g.updatePlaying()
+	if g.state != StatePlaying || g.hud().Status != "TIME\n0:01" {
+		t.Fatalf("one tick left: got state %v, status %q", g.state, g.hud().Status)
+	}
+	g.updatePlaying()
+	if g.state != StateGameOver || g.highScores.Best(ModeTimeAttack) != 250 {
+		t.Errorf("time up: got state %v, tables %v; want game over with 250", g.state, g.highScores)
+	}
+}
+
+func TestEndlessRefillsInWaves(t *testing.T) {
+	g := startMode(t, ModeEndless)
+	for wave := 2; wave <= 3; wave++ {
+		eatDots(g, g.maze.TotalDots())
+		g.updatePlaying()
+		if g.state != StatePlaying || g.level != wave {
+			t.Fatalf("wave %d: got state %v, level %d; want play to go on a level up", wave, g.state, g.level)
+		}
+		if g.maze.RemainingDots() < g.maze.TotalDots()-1 {
+			t.Errorf("wave %d: the maze should refill, %d of %d dots left", wave, g.maze.RemainingDots(), g.maze.TotalDots())
+		}
+	}
+	if got := g.hud().Status; got != "WAVE\n3" {
+		t.Errorf("status: got %q, want WAVE 3", got)
+	}
+}
+
+func TestSurvival(t *testing.T) {
+	g := startMode(t, ModeSurvival)
+	if g.lives != 1 || g.nextExtraLife != 0 {
+		t.Errorf("got %d lives, next extra life at %d; want one life and no extras", g.lives, g.nextExtraLife)
+	}
+	blinky := g.ghosts[Blinky]
+	blinky.InHouse, blinky.Mode = false, GhostChase
+	g.applyDifficulty()
+	start := blinky.Speed
+
+	m := g.mode.(*survivalMode)
+	for range 60 {
+		m.tick(g)
+	}
+	if g.score != survivalScore {
+		t.Errorf("after a second: got score %d, want %d", g.score, survivalScore)
+	}
+	m.ticks = survivalStepTicks
+	g.applyDifficulty()
+	if want := start * (1 + survivalSpeedStep); math.Abs(blinky.Speed-want) > 1e-9 {
+		t.Errorf("after %d ticks: got ghost speed %g, want %g", survivalStepTicks, blinky.Speed, want)
+	}
+	m.ticks = 1000 * survivalStepTicks
+	g.applyDifficulty()
+	if blinky.Speed != maxSpeed {
+		t.Errorf("ghost speed should stop at %d, got %g", maxSpeed, blinky.Speed)
+	}
+	if got := g.hud().Status; got != "TIME\n250:00" {
+		t.Errorf("status: got %q, want the time survived", got)
+	}
+
+	eatDots(g, g.maze.TotalDots())
+	g.updatePlaying()
+	if g.state != StatePlaying || g.level != 1 {
+		t.Errorf("a cleared maze should refill without a new level, got state %v, level %d", g.state, g.level)
+	}
+}
+
+func TestReplayKeepsMode(t *testing.T) {
+	g := startMode(t, ModeSurvival)
+	r := g.replay
+	g = New()
+	g.StartReplay(r)
+	if g.modeID != ModeSurvival || g.lives != 1 {
+		t.Errorf("got mode %q with %d lives, want a survival replay", g.modeID, g.lives)
+	}
+}
@@ -51,3 +51,24 @@ func TestPacManQueuedDirection(t *testing.T) {
 		}
 	}
 }
+
+func TestPacManThroughTunnelAndPortal(t *testing.T) {
+	m := NewMazeFromLayout(portalMaze)
+	p := NewPacManAt(TilePos{3, 1})
+	p.Dir = DirUp
+	for range 12 {
+		p.Move(m)
+	}
+	if p.TileX() != 3 || p.TileY() != 3 {
+		t.Errorf("got Pac-Man at %d, %d, want 3, 3 after going up through the tunnel", p.TileX(), p.TileY())
+	}
+
+	p = NewPacManAt(TilePos{3, 1})
+	p.Dir = DirLeft
+	for range 12 {
+		p.Move(m)
+	}
+	if p.TileX() != 5 || p.TileY() != 1 || p.Dir != DirLeft {
+		t.Errorf("got Pac-Man at %d, %d heading %v, want out of the other portal at 5, 1 heading left", p.TileX(), p.TileY(), p.Dir)
+	}
+}
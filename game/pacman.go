@@ -13,32 +13,52 @@ const (
 	DirRight
 )
 
+// String returns the lowercase name of the direction.
+func (d Direction) String() string {
+	switch d {
+	case DirUp:
+		return "up"
+	case DirDown:
+		return "down"
+	case DirLeft:
+		return "left"
+	case DirRight:
+		return "right"
+	}
+	return "none"
+}
+
 // PacMan represents the player-controlled Pac-Man entity.
 type PacMan struct {
 	X, Y       float64   // pixel position (center of sprite)
-	Dir        Direction  // current movement direction
-	NextDir    Direction  // queued direction from input
-	Speed      float64    // pixels per tick
-	AnimFrame  int        // 0, 1, 2 (closed, half, open)
-	AnimTimer  int        // ticks until next frame
+	Dir        Direction // current movement direction
+	NextDir    Direction // queued direction from input
+	Speed      float64   // pixels per tick
+	AnimFrame  int       // 0, 1, 2 (closed, half, open)
+	AnimTimer  int       // ticks until next frame
 	Alive      bool
-	DeathFrame int        // current death animation frame (0-10)
-	DeathTimer int        // ticks remaining in death animation
+	DeathFrame int // current death animation frame (0-10)
+	DeathTimer int // ticks remaining in death animation
 
 	lastCenterTX int // tile where last center processing happened
 	lastCenterTY int
 }
 
-// NewPacMan creates a new PacMan at the spawn position.
+// NewPacMan creates a new PacMan at the classic maze's spawn position.
 func NewPacMan() *PacMan {
+	return NewPacManAt(classicSpawns.PacMan)
+}
+
+// NewPacManAt creates a new PacMan on the given tile.
+func NewPacManAt(spawn TilePos) *PacMan {
 	return &PacMan{
-		X:              float64(PacmanSpawnX*TileSize + TileSize/2),
-		Y:              float64(PacmanSpawnY*TileSize + TileSize/2),
-		Dir:            DirNone,
-		Speed:          1.5,
-		Alive:          true,
-		lastCenterTX:   -1,
-		lastCenterTY:   -1,
+		X:            float64(spawn.X*TileSize + TileSize/2),
+		Y:            float64(spawn.Y*TileSize + TileSize/2),
+		Dir:          DirNone,
+		Speed:        1.5,
+		Alive:        true,
+		lastCenterTX: -1,
+		lastCenterTY: -1,
 	}
 }
 
@@ -71,8 +91,7 @@ func (p *PacMan) Move(m *Maze) {
 
 		// Check if NextDir leads to a passable tile; if so, switch.
 		if p.NextDir != DirNone {
-			nx, ny := nextTile(tileX, tileY, p.NextDir)
-			if m.IsPassable(nx, ny) {
+			if _, _, ok := m.CanMove(tileX, tileY, p.NextDir); ok {
 				p.Dir = p.NextDir
 				p.NextDir = DirNone
 			}
@@ -80,15 +99,15 @@ func (p *PacMan) Move(m *Maze) {
 
 		// Check if current Dir leads to a passable tile; if not, stop.
 		if p.Dir != DirNone {
-			nx, ny := nextTile(tileX, tileY, p.Dir)
-			if !m.IsPassable(nx, ny) {
+			if _, _, ok := m.CanMove(tileX, tileY, p.Dir); !ok {
 				p.Dir = DirNone
 			}
 		}
 	}
 pacMove:
 
-	// Advance position based on direction.
+	// Advance position based on direction, through tunnels and portals.
+	tileX, tileY := p.TileX(), p.TileY()
 	switch p.Dir {
 	case DirUp:
 		p.Y -= p.Speed
@@ -99,13 +118,7 @@ pacMove:
 	case DirRight:
 		p.X += p.Speed
 	}
-
-	// Tunnel wrapping
-	if p.X < 0 {
-		p.X += float64(MazeCols * TileSize)
-	} else if p.X >= float64(MazeCols*TileSize) {
-		p.X -= float64(MazeCols * TileSize)
-	}
+	crossTile(m, &p.X, &p.Y, tileX, tileY, p.Dir)
 
 	// Advance animation: cycle through frames every 4 ticks.
 	// AnimTimer counts total ticks; divide by 4 to get cycle position.
@@ -117,6 +130,19 @@ pacMove:
 	}
 }
 
+// crossTile moves a position that has just moved out of tile (tx, ty) in
+// direction d on to the tile Maze.Step leads to: across the board from a
+// tunnel, or out of a portal's pair.
+func crossTile(m *Maze, x, y *float64, tx, ty int, d Direction) {
+	rx, ry := int(math.Floor(*x/TileSize)), int(math.Floor(*y/TileSize))
+	if rx == tx && ry == ty {
+		return
+	}
+	nx, ny := m.Step(tx, ty, d)
+	*x += float64((nx - rx) * TileSize)
+	*y += float64((ny - ry) * TileSize)
+}
+
 // nextTile returns the tile coordinates one step in the given direction.
 func nextTile(x, y int, dir Direction) (int, int) {
 	switch dir {
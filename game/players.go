@@ -0,0 +1,62 @@
+package game
+
+// player holds the progress of the player who is not playing in a two-player
+// game. Players take turns, switching each time the one playing loses a life.
+type player struct {
+	maze          *Maze
+	score         int
+	lives         int
+	level         int
+	nextExtraLife int
+}
+
+// switchPlayer hands the turn to the waiting player, keeping the progress of
+// the one who was playing.
+func (g *Game) switchPlayer() {
+	next := g.waiting
+	g.waiting = player{
+		maze:          g.maze,
+		score:         g.score,
+		lives:         g.lives,
+		level:         g.level,
+		nextExtraLife: g.nextExtraLife,
+	}
+	g.setMaze(next.maze)
+	g.score = next.score
+	g.lives = next.lives
+	g.level = next.level
+	g.nextExtraLife = next.nextExtraLife
+	g.current = 1 - g.current
+}
+
+// hud returns what the HUD shows: each player's score in player order, the
+// mode's high score and status, and the lives and level of the player whose
+// turn it is.
+func (g *Game) hud() HUD {
+	h := HUD{
+		Scores:    []int{g.score},
+		HighScore: g.highScores.Best(g.modeID),
+		Lives:     g.lives,
+		Level:     g.level,
+		Fruit:     g.difficulty().Fruit,
+		Status:    g.mode.status(),
+		View:      g.viewRect(),
+	}
+	if g.players == 2 {
+		h.Scores = append(h.Scores, g.waiting.score)
+		if g.current == 1 {
+			h.Scores[0], h.Scores[1] = h.Scores[1], h.Scores[0]
+		}
+	}
+	return h
+}
+
+// inGame reports whether a game is being played, from READY! to the last
+// death.
+func (g *Game) inGame() bool {
+	switch g.state {
+	case StateReady, StatePlaying, StateDeath, StateLevelClear:
+		return true
+	}
+	return false
+}
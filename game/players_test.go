@@ -0,0 +1,59 @@
+package game
+
+import (
+	"slices"
+	"testing"
+)
+
+// loseLife kills Pac-Man and runs the death animation to its end.
+func loseLife(g *Game) {
+	g.lives--
+	g.state = StateDeath
+	g.stateTimer = 1
+	g.updateDeath()
+}
+
+func TestTwoPlayersTakeTurns(t *testing.T) {
+	g := New()
+	g.startGame(1, 2)
+	g.score = 120
+	g.maze.ConsumeDot(1, 1)
+
+	loseLife(g)
+	if g.current != 1 || g.state != StateReady {
+		t.Fatalf("after player one's death: current %d, state %v; want player two ready", g.current, g.state)
+	}
+	if g.score != 0 || g.lives != 3 || g.maze.TileAt(1, 1) != TileDot {
+		t.Errorf("player two should start fresh, got score %d, lives %d", g.score, g.lives)
+	}
+	if h := g.hud(); len(h.Scores) != 2 || h.Scores[0] != 120 || h.Scores[1] != 0 {
+		t.Errorf("HUD scores: got %v, want [120 0]", h.Scores)
+	}
+
+	loseLife(g)
+	if g.current != 0 || g.score != 120 || g.lives != 2 || g.maze.TileAt(1, 1) != TileEmpty {
+		t.Errorf("player one should resume with score 120, 2 lives and their maze; got score %d, lives %d", g.score, g.lives)
+	}
+}
+
+func TestTwoPlayerGameOver(t *testing.T) {
+	g := New()
+	g.startGame(1, 2)
+	g.score = 500
+	g.lives = 1
+	loseLife(g) // player one is out
+	if g.current != 1 {
+		t.Fatalf("player two should play, got player %d", g.current+1)
+	}
+	for g.lives > 1 {
+		loseLife(g)
+		if g.current != 1 {
+			t.Fatal("player two should keep playing once player one is out")
+		}
+	}
+	g.score = 300
+	loseLife(g)
+	if g.state != StateGameOver || !slices.Equal(g.highScores[ModeClassic], []int{500, 300}) {
+		t.Errorf("got state %v, high scores %v; want game over with [500 300]", g.state, g.highScores[ModeClassic])
+	}
+}
@@ -0,0 +1,258 @@
+package game
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// replayHeader is the first line of a replay file. Replays headed
+// replayHeaderV1 predate the rules line and are still read.
+const (
+	replayHeader   = "go-pacman replay 2"
+	replayHeaderV1 = "go-pacman replay 1"
+)
+
+// Replay is a recorded game: the seed its ghosts' random choices came from,
+// the game speed, number of players, mode, difficulty, maze, custom board and
+// rules it was played under, and every change of Pac-Man's queued direction.
+// Nothing else in play depends on the player, so playing the inputs back on
+// the same seed under the same rules and board reproduces the game exactly.
+type Replay struct {
+	Seed         int64
+	SpeedPercent int
+	Players      int    // 1 or 2, taking turns
+	Mode         string // one of Modes
+	Difficulty   string // Rules.Difficulty, empty to keep the game's
+	Maze         string // Rules.Maze, empty to keep the game's
+	Board        string // Maze.Hash of the custom board played instead of Maze, empty for none
+	Rules        string // Rules.Hash of the game's rules, empty if not known
+	Ticks        int    // ticks of play recorded
+	Inputs       []ReplayInput
+}
+
+// ReplayInput queues Dir on the given tick of play. Ticks count only while
+// Pac-Man can move, not during READY!, death or level-clear pauses.
+type ReplayInput struct {
+	Tick int
+	Dir  Direction
+}
+
+// WriteReplay writes r in the replay text format:
+//
+//	go-pacman replay 2
+//	seed 1718000000000000000
+//	speed 100
+//	players 1
+//	mode classic
+//	difficulty classic
+//	maze generated
+//	board 8b41d07a6c2e
+//	rules 3f9a0c12d4e7
+//	ticks 5400
+//	12 left
+//	96 up
+//
+// Each input line is a tick of play and the direction queued on it, as a
+// direction in the maze whatever the display orientation.
+func WriteReplay(w io.Writer, r *Replay) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, replayHeader)
+	fmt.Fprintf(bw, "seed %d\nspeed %d\nplayers %d\nmode %s\n", r.Seed, r.SpeedPercent, r.Players, r.Mode)
+	if r.Difficulty != "" {
+		fmt.Fprintf(bw, "difficulty %s\n", r.Difficulty)
+	}
+	if r.Maze != "" {
+		fmt.Fprintf(bw, "maze %s\n", r.Maze)
+	}
+	if r.Board != "" {
+		fmt.Fprintf(bw, "board %s\n", r.Board)
+	}
+	if r.Rules != "" {
+		fmt.Fprintf(bw, "rules %s\n", r.Rules)
+	}
+	fmt.Fprintf(bw, "ticks %d\n", r.Ticks)
+	for _, in := range r.Inputs {
+		fmt.Fprintf(bw, "%d %s\n", in.Tick, in.Dir)
+	}
+	return bw.Flush()
+}
+
+// ParseReplay reads a replay written by WriteReplay.
+func ParseReplay(rd io.Reader) (*Replay, error) {
+	sc := bufio.NewScanner(rd)
+	if !sc.Scan() || !slices.Contains([]string{replayHeader, replayHeaderV1}, strings.TrimSpace(sc.Text())) {
+		if err := sc.Err(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("line 1: not a replay, expected %q", replayHeader)
+	}
+	r := &Replay{SpeedPercent: 100, Players: 1, Mode: ModeClassic}
+	for line := 2; sc.Scan(); line++ {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: expected two fields, got %d", line, len(fields))
+		}
+		var err error
+		switch fields[0] {
+		case "seed":
+			r.Seed, err = strconv.ParseInt(fields[1], 10, 64)
+		case "speed":
+			r.SpeedPercent, err = strconv.Atoi(fields[1])
+		case "players":
+			r.Players, err = strconv.Atoi(fields[1])
+			if err == nil && (r.Players < 1 || r.Players > 2) {
+				err = fmt.Errorf("players must be 1 or 2")
+			}
+		case "mode":
+			r.Mode = fields[1]
+			if !validMode(r.Mode) {
+				err = fmt.Errorf("unknown mode %q", r.Mode)
+			}
+		case "difficulty":
+			r.Difficulty = fields[1]
+			_, err = DifficultyPreset(r.Difficulty)
+		case "maze":
+			r.Maze = fields[1]
+			if r.Maze != MazeClassic && r.Maze != MazeGenerated {
+				err = fmt.Errorf("unknown maze %q", r.Maze)
+			}
+		case "board":
+			r.Board = fields[1]
+		case "rules":
+			r.Rules = fields[1]
+		case "ticks":
+			r.Ticks, err = strconv.Atoi(fields[1])
+		default:
+			err = r.parseInput(fields[0], fields[1])
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// parseInput appends the input "<tick> <direction>".
+func (r *Replay) parseInput(tick, dir string) error {
+	t, err := strconv.Atoi(tick)
+	if err != nil {
+		return fmt.Errorf("unknown keyword %q", tick)
+	}
+	if n := len(r.Inputs); t < 0 || n > 0 && t < r.Inputs[n-1].Tick {
+		return fmt.Errorf("tick %d out of order", t)
+	}
+	for d := DirNone; d <= DirRight; d++ {
+		if d.String() == dir {
+			r.Inputs = append(r.Inputs, ReplayInput{Tick: t, Dir: d})
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown direction %q", dir)
+}
+
+// LoadReplay reads a replay file.
+func LoadReplay(path string) (*Replay, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("replay: %w", err)
+	}
+	defer f.Close()
+	r, err := ParseReplay(f)
+	if err != nil {
+		return nil, fmt.Errorf("replay %s: %w", path, err)
+	}
+	return r, nil
+}
+
+// SaveReplay writes a replay file.
+func SaveReplay(path string, r *Replay) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("replay: %w", err)
+	}
+	if err := WriteReplay(f, r); err != nil {
+		f.Close()
+		return fmt.Errorf("replay %s: %w", path, err)
+	}
+	return f.Close()
+}
+
+// StartReplay starts a new game that plays r back instead of reading the
+// keyboard. The replay's speed, mode, difficulty and maze replace the speed
+// setting, the chosen mode and the rules' difficulty and maze. A replay
+// recorded under other rules or on another custom board than the game's will
+// not play back the same, which is logged as a warning.
+func (g *Game) StartReplay(r *Replay) {
+	g.settings.SpeedPercent = r.SpeedPercent
+	g.modeID = cmp.Or(r.Mode, ModeClassic)
+	rules := g.rules
+	rules.Difficulty = cmp.Or(r.Difficulty, rules.Difficulty)
+	rules.Maze = cmp.Or(r.Maze, rules.Maze)
+	g.SetRules(rules)
+	if h := g.rules.Hash(); r.Rules != "" && r.Rules != h {
+		log.Printf("replay recorded under other rules (%s, not %s): it will not play back the same", r.Rules, h)
+	}
+	if h := g.boardHash(); r.Board != "" && r.Board != h {
+		log.Printf("replay recorded on another board (%s, not %s): it will not play back the same", r.Board, cmp.Or(h, "the rules' maze"))
+	}
+	g.startGame(r.Seed, r.Players)
+	g.replay = r
+	g.playback = true
+}
+
+// boardHash returns the Maze.Hash of the custom board in play, or "" if the
+// game plays the rules' maze.
+func (g *Game) boardHash() string {
+	if board := g.customBoard(); board != nil {
+		return board.Hash()
+	}
+	return ""
+}
+
+// ReplayFinished reports whether a replay started with StartReplay has played
+// all its recorded ticks, or ended in game over.
+func (g *Game) ReplayFinished() bool {
+	if !g.playback {
+		return false
+	}
+	return g.playTicks >= g.replay.Ticks || g.state == StateGameOver || g.state == StateTitle
+}
+
+// readInput queues Pac-Man's next direction for one tick of play: from the
+// replay during playback, otherwise from the keyboard, recording any change.
+func (g *Game) readInput() {
+	defer func() { g.playTicks++ }()
+	if g.playback {
+		for ; g.replayInput < len(g.replay.Inputs); g.replayInput++ {
+			in := g.replay.Inputs[g.replayInput]
+			if in.Tick > g.playTicks {
+				break
+			}
+			g.pacman.NextDir = in.Dir
+		}
+		return
+	}
+
+	before := g.pacman.NextDir
+	ReadInput(g.pacman, g.settings.Orientation)
+	if g.replay == nil {
+		return
+	}
+	if g.pacman.NextDir != before {
+		g.replay.Inputs = append(g.replay.Inputs, ReplayInput{Tick: g.playTicks, Dir: g.pacman.NextDir})
+	}
+	g.replay.Ticks = g.playTicks + 1
+}
@@ -0,0 +1,165 @@
+package game
+
+import (
+	"bytes"
+	"log"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestReplayRoundTrip(t *testing.T) {
+	r := &Replay{
+		Seed:         -42,
+		SpeedPercent: 75,
+		Players:      2,
+		Mode:         ModeSurvival,
+		Difficulty:   DifficultyArcade,
+		Maze:         MazeGenerated,
+		Board:        NewMaze().Hash(),
+		Rules:        DefaultRules().Hash(),
+		Ticks:        600,
+		Inputs:       []ReplayInput{{0, DirLeft}, {12, DirUp}, {12, DirDown}, {300, DirNone}},
+	}
+	var buf bytes.Buffer
+	if err := WriteReplay(&buf, r); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ParseReplay(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, r) {
+		t.Errorf("got %+v, want %+v", got, r)
+	}
+}
+
+func TestParseReplayV1(t *testing.T) {
+	r, err := ParseReplay(strings.NewReader(replayHeaderV1 + "\nseed 7\nticks 10\n3 up\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Seed != 7 || r.Rules != "" || len(r.Inputs) != 1 {
+		t.Errorf("got %+v", r)
+	}
+}
+
+func TestParseReplayErrors(t *testing.T) {
+	tests := []struct {
+		src, want string
+	}{
+		{"a replay\n", "line 1:"},
+		{replayHeader + "\nseed x\n", "line 2:"},
+		{replayHeader + "\nspeed 100\n5 sideways\n", "line 3:"},
+		{replayHeader + "\n10 up\n5 down\n", "line 3:"},
+		{replayHeader + "\nlevel 3\n", "line 2:"},
+		{replayHeader + "\n1 up down\n", "line 2:"},
+		{replayHeader + "\nplayers 3\n", "line 2:"},
+		{replayHeader + "\nseed 1\nmode marathon\n", "line 3:"},
+		{replayHeader + "\ndifficulty insane\n", "line 2:"},
+		{replayHeader + "\nseed 1\nmaze spiral\n", "line 3:"},
+	}
+	for _, tt := range tests {
+		_, err := ParseReplay(strings.NewReader(tt.src))
+		if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+			t.Errorf("%q: got error %v, want one starting %q", tt.src, err, tt.want)
+		}
+	}
+}
+
+// playReplay plays r back in a new game and returns it when the replay ends.
+func playReplay(t *testing.T, r *Replay) *Game {
+	t.Helper()
+	g := New()
+	g.StartReplay(r)
+	for i := 0; !g.ReplayFinished(); i++ {
+		if i > 100000 {
+			t.Fatal("replay did not finish")
+		}
+		g.Update()
+	}
+	return g
+}
+
+func TestReplayIsDeterministic(t *testing.T) {
+	r := &Replay{
+		Seed:         7,
+		SpeedPercent: 100,
+		Players:      1,
+		Ticks:        1800,
+		Inputs:       []ReplayInput{{0, DirLeft}, {90, DirDown}, {200, DirRight}, {400, DirUp}, {700, DirLeft}},
+	}
+	a := playReplay(t, r)
+	// Random choices made in between must not leak into the next game
+	rng.Int()
+	b := playReplay(t, r)
+
+	if a.score != b.score || a.lives != b.lives || a.state != b.state {
+		t.Errorf("score, lives, state: %d %d %d, then %d %d %d", a.score, a.lives, a.state, b.score, b.lives, b.state)
+	}
+	if *a.pacman != *b.pacman {
+		t.Errorf("Pac-Man: %+v, then %+v", *a.pacman, *b.pacman)
+	}
+	for i := range a.ghosts {
+		if a.ghosts[i].X != b.ghosts[i].X || a.ghosts[i].Y != b.ghosts[i].Y || a.ghosts[i].Mode != b.ghosts[i].Mode {
+			t.Errorf("ghost %d: %+v, then %+v", i, *a.ghosts[i], *b.ghosts[i])
+		}
+	}
+	if a.score == 0 {
+		t.Error("the replay should have eaten some dots")
+	}
+}
+
+func TestReplayKeepsDifficulty(t *testing.T) {
+	g := New()
+	g.StartReplay(&Replay{Seed: 1, SpeedPercent: 100, Players: 1, Difficulty: DifficultyArcade, Ticks: 1})
+	want, err := DifficultyPreset(DifficultyArcade)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(g.difficulty(), want.At(1)) {
+		t.Errorf("got level 1 difficulty %+v, want the arcade preset's", g.difficulty())
+	}
+}
+
+func TestReplayKeepsMaze(t *testing.T) {
+	g := New()
+	g.StartReplay(&Replay{Seed: 1, SpeedPercent: 100, Players: 1, Maze: MazeGenerated, Ticks: 1})
+	if slices.Equal(g.maze.layout, mazeLayout) {
+		t.Error("a replay of a generated-maze game should play on a generated maze")
+	}
+}
+
+func TestReplayRecordsBoard(t *testing.T) {
+	g := New()
+	g.startGame(1, 1)
+	if g.replay.Board != "" {
+		t.Errorf("a game on the rules' maze recorded the board %q", g.replay.Board)
+	}
+
+	layout := slices.Clone(mazeLayout)
+	layout[1] = "#.....#......##............#"
+	g.board = NewMazeFromLayout(layout)
+	g.startGame(1, 1)
+	r := g.replay
+	if r.Board == "" || r.Board != g.board.Hash() {
+		t.Errorf("got board %q, want the custom board's hash %q", r.Board, g.board.Hash())
+	}
+	if r.Board == NewMaze().Hash() {
+		t.Error("another board should have another hash")
+	}
+
+	var logged bytes.Buffer
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(&logged)
+	New().StartReplay(r)
+	if !strings.Contains(logged.String(), "another board") {
+		t.Errorf("replaying on the rules' maze should warn, logged %q", logged.String())
+	}
+	logged.Reset()
+	g.StartReplay(r)
+	if logged.Len() != 0 {
+		t.Errorf("replaying on the same board should not warn, logged %q", logged.String())
+	}
+}
@@ -0,0 +1,220 @@
+package game
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+)
+
+// maxSpeed is the fastest anything may move, in pixels per tick. Faster
+// entities could step over a tile center without turning there.
+const maxSpeed = TileSize / 2
+
+// Rules holds the scoring, lives, timing and difficulty rules of a game.
+// DefaultRules gives the usual ones; a rules file can change any of them for
+// house-rule games.
+type Rules struct {
+	DotScore         int `json:"dot_score"`
+	PowerPelletScore int `json:"power_pellet_score"`
+	GhostScore       int `json:"ghost_score"` // first ghost eaten per power pellet; each next one doubles
+	StartingLives    int `json:"starting_lives"`
+	ExtraLifeScore   int `json:"extra_life_score"` // score for the first extra life, 0 for none
+	ExtraLifeEvery   int `json:"extra_life_every"` // score between further extra lives, 0 for only one
+
+	// State durations in ticks, at 60 TPS
+	ReadyTicks      int `json:"ready_ticks"`
+	DeathTicks      int `json:"death_ticks"`
+	LevelClearTicks int `json:"level_clear_ticks"`
+	GameOverTicks   int `json:"game_over_ticks"`
+
+	// Difficulty names the preset table of per-level difficulty, one of
+	// DifficultyPresets.
+	Difficulty string `json:"difficulty"`
+
+	// Levels replaces the preset's first levels: entry i is level i+1. If it
+	// is longer than the preset, its last entry repeats instead.
+	Levels []DifficultyParams `json:"levels,omitempty"`
+
+	// Maze is MazeClassic for the arcade board, or MazeGenerated for a new
+	// generated board each level.
+	Maze string `json:"maze"`
+}
+
+// Maze rules.
+const (
+	MazeClassic   = "classic"
+	MazeGenerated = "generated"
+)
+
+// DefaultRules returns the rules used when no rules file is given.
+func DefaultRules() Rules {
+	return Rules{
+		DotScore:         10,
+		PowerPelletScore: 50,
+		GhostScore:       200,
+		StartingLives:    3,
+		ExtraLifeScore:   10000,
+		ReadyTicks:       120,
+		DeathTicks:       120,
+		LevelClearTicks:  120,
+		GameOverTicks:    180,
+		Difficulty:       DifficultyClassic,
+		Maze:             MazeClassic,
+	}
+}
+
+// ParseRules reads a rules file. Rules missing from the file keep their
+// defaults, and each entry of "levels" starts from the preset's values for its
+// level, so a file only lists what it changes:
+//
+//	{
+//	  "starting_lives": 5,
+//	  "extra_life_score": 20000,
+//	  "extra_life_every": 20000,
+//	  "difficulty": "arcade",
+//	  "levels": [{}, {"ghost_speed": 1.6, "fruit": "key"}]
+//	}
+//
+// Unknown keys are errors, so that a misspelled rule is not silently ignored.
+func ParseRules(data []byte) (Rules, error) {
+	r := DefaultRules()
+	var file struct {
+		*Rules
+		Levels []json.RawMessage `json:"levels"`
+	}
+	file.Rules = &r
+	if err := decodeStrict(data, &file); err != nil {
+		return DefaultRules(), err
+	}
+	preset, err := DifficultyPreset(r.Difficulty)
+	if err != nil {
+		return DefaultRules(), fmt.Errorf("difficulty: %w", err)
+	}
+	for i, raw := range file.Levels {
+		p := preset.At(i + 1)
+		p.Schedule = slices.Clone(p.Schedule) // levels past the preset's last share it
+		if err := decodeStrict(raw, &p); err != nil {
+			return DefaultRules(), fmt.Errorf("levels[%d]: %w", i, err)
+		}
+		r.Levels = append(r.Levels, p)
+	}
+	if err := r.Validate(); err != nil {
+		return DefaultRules(), err
+	}
+	return r, nil
+}
+
+func decodeStrict(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
+
+// LoadRules reads and validates a rules file.
+func LoadRules(path string) (Rules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return DefaultRules(), fmt.Errorf("rules: %w", err)
+	}
+	r, err := ParseRules(data)
+	if err != nil {
+		return r, fmt.Errorf("rules %s: %w", path, err)
+	}
+	return r, nil
+}
+
+// Validate reports every rule that is out of range.
+func (r Rules) Validate() error {
+	var errs []error
+	positive := func(name string, v int) {
+		if v <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be positive, got %d", name, v))
+		}
+	}
+	notNegative := func(name string, v int) {
+		if v < 0 {
+			errs = append(errs, fmt.Errorf("%s must not be negative, got %d", name, v))
+		}
+	}
+	notNegative("dot_score", r.DotScore)
+	notNegative("power_pellet_score", r.PowerPelletScore)
+	notNegative("ghost_score", r.GhostScore)
+	positive("starting_lives", r.StartingLives)
+	notNegative("extra_life_score", r.ExtraLifeScore)
+	notNegative("extra_life_every", r.ExtraLifeEvery)
+	if r.ExtraLifeScore == 0 && r.ExtraLifeEvery > 0 {
+		errs = append(errs, errors.New("extra_life_every needs an extra_life_score for the first extra life"))
+	}
+	positive("ready_ticks", r.ReadyTicks)
+	positive("death_ticks", r.DeathTicks)
+	positive("level_clear_ticks", r.LevelClearTicks)
+	positive("game_over_ticks", r.GameOverTicks)
+	if _, err := DifficultyPreset(r.Difficulty); err != nil {
+		errs = append(errs, fmt.Errorf("difficulty: %w", err))
+	}
+	if r.Maze != MazeClassic && r.Maze != MazeGenerated {
+		errs = append(errs, fmt.Errorf("maze must be %q or %q, got %q", MazeClassic, MazeGenerated, r.Maze))
+	}
+	for i, p := range r.Levels {
+		if err := p.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("levels[%d]: %w", i, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Table returns the per-level difficulty table: the preset with the first
+// levels replaced by Levels.
+func (r Rules) Table() DifficultyTable {
+	t, err := DifficultyPreset(r.Difficulty)
+	if err != nil {
+		t = classicTable()
+	}
+	if len(r.Levels) == 0 {
+		return t
+	}
+	return append(slices.Clone(DifficultyTable(r.Levels)), t[min(len(r.Levels), len(t)):]...)
+}
+
+// Hash returns a short fingerprint of the rules. Replays record it, so that
+// playing one back under other rules can be noticed.
+func (r Rules) Hash() string {
+	data, err := json.Marshal(r)
+	if err != nil {
+		panic(err) // rules are plain data
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:6])
+}
+
+// DifficultyAt returns the difficulty of a level.
+func (r Rules) DifficultyAt(level int) DifficultyParams {
+	return r.Table().At(level)
+}
+
+// nextExtraLife returns the score at which the extra life after the one at
+// score awarded comes, or 0 if there are no more.
+func (r Rules) nextExtraLife(awarded int) int {
+	if r.ExtraLifeEvery == 0 {
+		return 0
+	}
+	return awarded + r.ExtraLifeEvery
+}
+
+// SetRules replaces the game's rules. Scores, timings and the difficulty table
+// apply at once, even mid-game; the maze applies from the next level and the
+// starting lives from the next game.
+func (g *Game) SetRules(r Rules) {
+	g.rules = r
+	g.table = r.Table()
+}
+
+// difficulty returns the difficulty of the current level.
+func (g *Game) difficulty() DifficultyParams {
+	return g.table.At(g.level)
+}
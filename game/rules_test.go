@@ -0,0 +1,191 @@
+package game
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDefaultRulesValid(t *testing.T) {
+	if err := DefaultRules().Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseRulesOverrides(t *testing.T) {
+	r, err := ParseRules([]byte(`{
+		"starting_lives": 5,
+		"dot_score": 20,
+		"difficulty": "arcade",
+		"levels": [{}, {"ghost_speed": 1.6, "fruit": "key", "schedule": [600]}]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.StartingLives != 5 || r.DotScore != 20 || r.PowerPelletScore != 50 {
+		t.Errorf("got lives %d, dot %d, pellet %d; want 5, 20 and the default 50", r.StartingLives, r.DotScore, r.PowerPelletScore)
+	}
+	arcade := arcadeTable()
+	if len(r.Levels) != 2 {
+		t.Fatalf("got %d levels, want 2", len(r.Levels))
+	}
+	if d := r.DifficultyAt(1); d.GhostSpeed != arcade[0].GhostSpeed || d.Fruit != FruitCherries {
+		t.Errorf("an empty level entry should be the preset's level, got %+v", d)
+	}
+	d := r.DifficultyAt(2)
+	if d.GhostSpeed != 1.6 || d.Fruit != FruitKey || len(d.Schedule) != 1 || d.PacManSpeed != arcade[1].PacManSpeed {
+		t.Errorf("level 2 should change only ghost speed, fruit and schedule, got %+v", d)
+	}
+	if d := r.DifficultyAt(3); d.GhostSpeed != arcade[2].GhostSpeed {
+		t.Errorf("level 3 should come from the preset, got ghost speed %v", d.GhostSpeed)
+	}
+	if len(arcade[1].Schedule) != 7 {
+		t.Error("overriding a schedule must not change the preset")
+	}
+}
+
+func TestRulesLevelsPastPreset(t *testing.T) {
+	levels := make([]string, tableLevels+2)
+	for i := range levels {
+		levels[i] = "{}"
+	}
+	levels[len(levels)-1] = `{"ghost_speed": 2.5, "schedule": [1, 2]}`
+	r, err := ParseRules([]byte(`{"levels": [` + strings.Join(levels, ",") + `]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := r.DifficultyAt(tableLevels + 1); d.GhostSpeed == 2.5 || len(d.Schedule) == 2 {
+		t.Errorf("level %d should keep the preset's last entry, got %+v", tableLevels+1, d)
+	}
+	for _, level := range []int{tableLevels + 2, 99} {
+		if d := r.DifficultyAt(level); d.GhostSpeed != 2.5 {
+			t.Errorf("level %d: got ghost speed %v, want the last entry's 2.5", level, d.GhostSpeed)
+		}
+	}
+}
+
+func TestParseRulesErrors(t *testing.T) {
+	tests := []struct {
+		name, json, want string
+	}{
+		{"unknown key", `{"starting_live": 5}`, "starting_live"},
+		{"unknown level key", `{"levels": [{"ghost_sped": 1}]}`, "levels[0]"},
+		{"no lives", `{"starting_lives": 0}`, "starting_lives must be positive"},
+		{"negative score", `{"ghost_score": -200}`, "ghost_score"},
+		{"too fast", `{"levels": [{}, {"pacman_speed": 9}]}`, "levels[1]: pacman_speed"},
+		{"recurring without first", `{"extra_life_score": 0, "extra_life_every": 5000}`, "extra_life_every"},
+		{"unknown preset", `{"difficulty": "nightmare"}`, "unknown difficulty"},
+		{"unknown maze", `{"maze": "spiral"}`, "maze must be"},
+		{"unknown fruit", `{"levels": [{"fruit": "banana"}]}`, "unknown fruit"},
+		{"empty phase", `{"levels": [{"schedule": [420, 0]}]}`, "schedule"},
+		{"elroy order", `{"levels": [{"elroy1_dots": 10, "elroy2_dots": 20}]}`, "elroy2_dots"},
+		{"not json", `starting_lives = 5`, "invalid character"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseRules([]byte(tt.json))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want one mentioning %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecurringExtraLives(t *testing.T) {
+	tests := []struct {
+		name         string
+		first, every int
+		score        int
+		wantLives    int
+	}{
+		{"below the first", 10000, 0, 9990, 3},
+		{"once only", 10000, 0, 50000, 4},
+		{"every 20,000", 20000, 20000, 65000, 6},
+		{"none", 0, 0, 99999, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := New()
+			r := DefaultRules()
+			r.ExtraLifeScore, r.ExtraLifeEvery = tt.first, tt.every
+			g.SetRules(r)
+			g.startGame(1, 1)
+			// Score in steps, as play would, then once more past each award
+			for s := 0; s <= tt.score; s += 5000 {
+				g.score = s
+				g.checkDotConsumption()
+			}
+			g.score = tt.score
+			g.checkDotConsumption()
+			if g.lives != tt.wantLives {
+				t.Errorf("got %d lives, want %d", g.lives, tt.wantLives)
+			}
+		})
+	}
+}
+
+func TestRulesApplyToGame(t *testing.T) {
+	r := DefaultRules()
+	r.StartingLives = 1
+	r.DotScore = 25
+	r.GhostScore = 100
+	r.ReadyTicks = 30
+	r.GameOverTicks = 10
+	g := New()
+	g.SetRules(r)
+	g.startGame(1, 1)
+	if g.lives != 1 || g.stateTimer != 30 {
+		t.Errorf("got %d lives and a %d tick READY!, want 1 and 30", g.lives, g.stateTimer)
+	}
+
+	g.pacman.X = float64(1*TileSize + TileSize/2)
+	g.pacman.Y = float64(1*TileSize + TileSize/2)
+	g.checkDotConsumption()
+	if g.score != 25 {
+		t.Errorf("dot score: got %d, want 25", g.score)
+	}
+	g.ghostsEatenCombo = 2
+	if s := g.ghostEatScore(); s != 400 {
+		t.Errorf("third ghost: got %d, want 400", s)
+	}
+
+	loseLife(g)
+	if g.state != StateGameOver || g.stateTimer != 10 {
+		t.Errorf("got state %v with timer %d, want game over for 10 ticks", g.state, g.stateTimer)
+	}
+}
+
+func TestDeathAnimationFitsDeathTicks(t *testing.T) {
+	for _, ticks := range []int{20, 120, 600} {
+		r := DefaultRules()
+		r.DeathTicks = ticks
+		g := New()
+		g.SetRules(r)
+		g.startGame(1, 1)
+		g.state = StateDeath
+		g.stateTimer = ticks
+		var frames []int
+		for {
+			g.updateDeath()
+			if g.state != StateDeath {
+				break
+			}
+			if len(frames) == 0 || frames[len(frames)-1] != g.pacman.DeathFrame {
+				frames = append(frames, g.pacman.DeathFrame)
+			}
+		}
+		if last := frames[len(frames)-1]; last != len(sprites.PacManDeath)-1 {
+			t.Errorf("death_ticks %d: the animation stopped at frame %d (%v)", ticks, last, frames)
+		}
+	}
+}
+
+func TestRulesHash(t *testing.T) {
+	r := DefaultRules()
+	if r.Hash() != DefaultRules().Hash() {
+		t.Error("the same rules should have the same hash")
+	}
+	r.StartingLives = 5
+	if r.Hash() == DefaultRules().Hash() {
+		t.Error("other rules should have another hash")
+	}
+}
@@ -0,0 +1,403 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// Speed multiplier bounds and step for the game speed setting, in percent.
+const (
+	MinSpeedPercent  = 50
+	MaxSpeedPercent  = 100
+	speedPercentStep = 5
+
+	MaxVolume  = 100 // volumes are percentages
+	volumeStep = 10
+)
+
+// Settings holds player preferences, including the accessibility and audio
+// options. They are saved as JSON between runs.
+type Settings struct {
+	Language        string `json:"language"`         // empty follows the environment
+	Theme           string `json:"theme"`            // theme ID
+	GhostLetters    bool   `json:"ghost_letters"`    // draw each ghost's initial on its body
+	ReducedFlashing bool   `json:"reduced_flashing"` // no power pellet blink or level-clear wall strobe
+	Minimap         bool   `json:"minimap"`          // show the whole maze and the ghosts beside the view
+	SpeedPercent    int    `json:"speed_percent"`    // multiplier applied to all entity speeds
+	MasterVolume    int    `json:"master_volume"`
+	SFXVolume       int    `json:"sfx_volume"`
+	MusicVolume     int    `json:"music_volume"`
+	Muted           bool   `json:"muted"`
+	WindowScale     int    `json:"window_scale"` // window size as a multiple of the game screen
+	Fullscreen      bool   `json:"fullscreen"`
+	VSync           bool   `json:"vsync"`
+	Resizable       bool   `json:"resizable"`     // the window can be resized, letterboxing the game
+	ScreenFilter    string `json:"screen_filter"` // FilterNone, FilterScanlines or FilterCRT
+	Orientation     int    `json:"orientation"`   // display rotation in degrees clockwise: 0, 90, 180 or 270
+	Cocktail        bool   `json:"cocktail"`      // turn the screen for player two in two-player games
+}
+
+// DefaultSettings returns the settings used when none are configured.
+func DefaultSettings() Settings {
+	return Settings{
+		Theme:        Themes[0].ID,
+		SpeedPercent: MaxSpeedPercent,
+		MasterVolume: MaxVolume,
+		SFXVolume:    MaxVolume,
+		MusicVolume:  MaxVolume * 7 / 10,
+		WindowScale:  DefaultWindowScale,
+		VSync:        true,
+		Resizable:    true,
+		ScreenFilter: FilterNone,
+	}
+}
+
+// DefaultSettingsPath returns where settings are saved: go-pacman/settings.json
+// in the user's configuration directory.
+func DefaultSettingsPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "go-pacman", "settings.json"), nil
+}
+
+// LoadSettings reads settings saved by SaveSettings. A missing file gives the
+// defaults, as do fields missing from the file.
+func LoadSettings(path string) (Settings, error) {
+	s := DefaultSettings()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return DefaultSettings(), fmt.Errorf("settings %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// SaveSettings writes settings to path, creating its directory if needed.
+func SaveSettings(path string, s Settings) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// speedScale returns the entity speed multiplier.
+func (s Settings) speedScale() float64 {
+	return float64(s.SpeedPercent) / 100
+}
+
+// SetSettings replaces the game's settings, clamping the game speed, volumes
+// and window scale to their allowed ranges. The language and theme are
+// applied separately with SetLanguage and SetTheme, the window settings with
+// ApplyWindowSettings.
+func (g *Game) SetSettings(s Settings) {
+	s.SpeedPercent = min(max(s.SpeedPercent, MinSpeedPercent), MaxSpeedPercent)
+	s.MasterVolume = clampVolume(s.MasterVolume)
+	s.SFXVolume = clampVolume(s.SFXVolume)
+	s.MusicVolume = clampVolume(s.MusicVolume)
+	s.WindowScale = min(max(s.WindowScale, 1), MaxWindowScale)
+	s.ScreenFilter = validFilter(s.ScreenFilter)
+	s.Orientation = validOrientation(s.Orientation)
+	g.settings = s
+	g.applyDifficulty()
+	g.sound.ApplySettings(s)
+}
+
+// Settings returns the game's current settings.
+func (g *Game) Settings() Settings {
+	return g.settings
+}
+
+// SetSettingsPath makes the game save its settings to path whenever the
+// player changes them, on top of saved, the settings the file holds. Only
+// changes made in the game are saved: settings given on the command line for
+// this run stay out of the file. With no path set, nothing is saved.
+func (g *Game) SetSettingsPath(path string, saved Settings) {
+	g.settingsPath = path
+	g.savedSettings = saved
+	g.runSettings = g.settings
+}
+
+// saveSettings writes the settings changed in the game since the last save
+// to the settings path, if one is set. A failed save only loses the
+// preferences, so it is logged rather than stopping the game.
+func (g *Game) saveSettings() {
+	if g.settingsPath == "" {
+		return
+	}
+	g.savedSettings = mergeSettings(g.savedSettings, g.runSettings, g.settings)
+	g.runSettings = g.settings
+	if err := SaveSettings(g.settingsPath, g.savedSettings); err != nil {
+		log.Printf("saving settings: %v", err)
+	}
+}
+
+// mergeSettings returns saved with every field that changed from before to
+// after set to its value after.
+func mergeSettings(saved, before, after Settings) Settings {
+	dst := reflect.ValueOf(&saved).Elem()
+	b, a := reflect.ValueOf(before), reflect.ValueOf(after)
+	for i := range dst.NumField() {
+		if !b.Field(i).Equal(a.Field(i)) {
+			dst.Field(i).Set(a.Field(i))
+		}
+	}
+	return saved
+}
+
+// toggleMute mutes or unmutes all sound and saves the change.
+func (g *Game) toggleMute() {
+	g.settings.Muted = !g.settings.Muted
+	g.sound.ApplySettings(g.settings)
+	g.saveSettings()
+}
+
+func clampVolume(v int) int {
+	return min(max(v, 0), MaxVolume)
+}
+
+// volumeItem returns a settings row that steps one volume level.
+func volumeItem(label string, level func(s *Settings) *int) settingItem {
+	return settingItem{
+		label: label,
+		value: func(g *Game) string { return fmt.Sprintf("%d%%", *level(&g.settings)) },
+		change: func(g *Game, delta int) {
+			v := level(&g.settings)
+			*v = clampVolume(*v + delta*volumeStep)
+			g.sound.ApplySettings(g.settings)
+		},
+	}
+}
+
+// onOff formats a boolean setting.
+func onOff(b bool) string {
+	if b {
+		return tr(msgOn)
+	}
+	return tr(msgOff)
+}
+
+// settingItem is one adjustable row on the settings screen.
+type settingItem struct {
+	label  string // message ID
+	value  func(g *Game) string
+	change func(g *Game, delta int) // delta is -1 for left, +1 for right
+}
+
+// settingItems lists the rows of the settings screen, top to bottom.
+var settingItems = []settingItem{
+	{
+		label: msgSettingLanguage,
+		value: func(g *Game) string { return tr(msgLanguageName) },
+		change: func(g *Game, delta int) {
+			codes := Languages()
+			i := 0
+			for j, code := range codes {
+				if code == language {
+					i = j
+				}
+			}
+			language = codes[wrapIndex(i+delta, len(codes))]
+			g.settings.Language = language
+		},
+	},
+	{
+		label: msgSettingTheme,
+		value: func(g *Game) string { return theme.Name },
+		change: func(g *Game, delta int) {
+			i := 0
+			for j, t := range Themes {
+				if t.ID == theme.ID {
+					i = j
+				}
+			}
+			next := Themes[wrapIndex(i+delta, len(Themes))]
+			// Keep a sprite sheet chosen on the command line
+			next.SpriteSheet = theme.SpriteSheet
+			if err := g.SetTheme(next); err != nil {
+				// The sheet went missing since startup; built-in themes
+				// without a sheet always apply.
+				next.SpriteSheet = ""
+				g.SetTheme(next)
+			}
+		},
+	},
+	{
+		label:  msgSettingGhostLetters,
+		value:  func(g *Game) string { return onOff(g.settings.GhostLetters) },
+		change: func(g *Game, delta int) { g.settings.GhostLetters = !g.settings.GhostLetters },
+	},
+	{
+		label:  msgSettingReducedFlashing,
+		value:  func(g *Game) string { return onOff(g.settings.ReducedFlashing) },
+		change: func(g *Game, delta int) { g.settings.ReducedFlashing = !g.settings.ReducedFlashing },
+	},
+	{
+		label: msgSettingMinimap,
+		value: func(g *Game) string { return onOff(g.settings.Minimap) },
+		change: func(g *Game, delta int) {
+			g.settings.Minimap = !g.settings.Minimap
+			g.applyWindowSize() // the minimap widens the screen
+		},
+	},
+	{
+		label: msgSettingGameSpeed,
+		value: func(g *Game) string { return fmt.Sprintf("%d%%", g.settings.SpeedPercent) },
+		change: func(g *Game, delta int) {
+			p := g.settings.SpeedPercent + delta*speedPercentStep
+			g.settings.SpeedPercent = min(max(p, MinSpeedPercent), MaxSpeedPercent)
+			g.applyDifficulty()
+		},
+	},
+	volumeItem(msgSettingMasterVolume, func(s *Settings) *int { return &s.MasterVolume }),
+	volumeItem(msgSettingSFXVolume, func(s *Settings) *int { return &s.SFXVolume }),
+	volumeItem(msgSettingMusicVolume, func(s *Settings) *int { return &s.MusicVolume }),
+	{
+		label:  msgSettingMute,
+		value:  func(g *Game) string { return onOff(g.settings.Muted) },
+		change: func(g *Game, delta int) { g.toggleMute() },
+	},
+	{
+		label: msgSettingWindowScale,
+		value: func(g *Game) string { return fmt.Sprintf("%dX", g.settings.WindowScale) },
+		change: func(g *Game, delta int) {
+			g.settings.WindowScale = min(max(g.settings.WindowScale+delta, 1), MaxWindowScale)
+			g.applyWindowSize()
+		},
+	},
+	{
+		label: msgSettingFullscreen,
+		value: func(g *Game) string { return onOff(g.settings.Fullscreen) },
+		change: func(g *Game, delta int) {
+			g.settings.Fullscreen = !g.settings.Fullscreen
+			ebiten.SetFullscreen(g.settings.Fullscreen)
+		},
+	},
+	{
+		label: msgSettingVSync,
+		value: func(g *Game) string { return onOff(g.settings.VSync) },
+		change: func(g *Game, delta int) {
+			g.settings.VSync = !g.settings.VSync
+			ebiten.SetVsyncEnabled(g.settings.VSync)
+		},
+	},
+	{
+		label: msgSettingResizable,
+		value: func(g *Game) string { return onOff(g.settings.Resizable) },
+		change: func(g *Game, delta int) {
+			g.settings.Resizable = !g.settings.Resizable
+			applyResizable(g.settings.Resizable)
+		},
+	},
+	{
+		label: msgSettingScreenFilter,
+		value: func(g *Game) string { return tr(filterNames[g.settings.ScreenFilter]) },
+		change: func(g *Game, delta int) {
+			i := 0
+			for j, f := range ScreenFilters {
+				if f == g.settings.ScreenFilter {
+					i = j
+				}
+			}
+			g.settings.ScreenFilter = ScreenFilters[wrapIndex(i+delta, len(ScreenFilters))]
+		},
+	},
+	{
+		label: msgSettingRotation,
+		value: func(g *Game) string { return fmt.Sprintf("%d", g.settings.Orientation) },
+		change: func(g *Game, delta int) {
+			g.settings.Orientation = (g.settings.Orientation + 360 + delta*90) % 360
+			g.applyWindowSize()
+		},
+	},
+	{
+		label:  msgSettingCocktail,
+		value:  func(g *Game) string { return onOff(g.settings.Cocktail) },
+		change: func(g *Game, delta int) { g.settings.Cocktail = !g.settings.Cocktail },
+	},
+}
+
+// wrapIndex wraps i into [0, n).
+func wrapIndex(i, n int) int {
+	return ((i % n) + n) % n
+}
+
+// updateSettings moves the cursor with up/down, changes the selected row with
+// left/right and returns to the title screen on Escape, saving the settings.
+func (g *Game) updateSettings() {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		g.saveSettings()
+		g.state = StateTitle
+		return
+	}
+	switch justPressedArrow(g.displayRotation()) {
+	case DirUp:
+		g.settingsCursor = wrapIndex(g.settingsCursor-1, len(settingItems))
+	case DirDown:
+		g.settingsCursor = wrapIndex(g.settingsCursor+1, len(settingItems))
+	case DirLeft:
+		settingItems[g.settingsCursor].change(g, -1)
+	case DirRight:
+		settingItems[g.settingsCursor].change(g, 1)
+	}
+}
+
+// Layout of the settings list. Rows beyond settingsVisibleRows scroll.
+const (
+	settingsTop         = 64
+	settingsRowHeight   = 12
+	settingsVisibleRows = 13
+)
+
+// settingsScroll returns the first visible row, keeping the cursor in the
+// middle of the list where the list is long enough.
+func settingsScroll(cursor, rows int) int {
+	first := min(max(cursor-settingsVisibleRows/2, 0), rows-settingsVisibleRows)
+	return max(first, 0)
+}
+
+// drawSettings draws the settings screen with the selected row highlighted.
+func (g *Game) drawSettings(screen Canvas) {
+	centered := &TextOptions{Color: theme.Palette.Text, Align: AlignCenter}
+	ox, oy := g.menuOrigin()
+	cx := ox + MinScreenWidth/2
+	DrawTextWith(screen, tr(msgSettings), cx, oy+40, centered)
+	first := settingsScroll(g.settingsCursor, len(settingItems))
+	last := min(first+settingsVisibleRows, len(settingItems))
+	for i := first; i < last; i++ {
+		item := settingItems[i]
+		c := theme.Palette.Text
+		if i == g.settingsCursor {
+			c = theme.Palette.Highlight
+		}
+		y := oy + settingsTop + (i-first)*settingsRowHeight
+		DrawText(screen, tr(item.label), ox+16, y, c)
+		DrawText(screen, item.value(g), ox+128, y, c)
+	}
+	if first > 0 {
+		DrawTextWith(screen, "↑", cx, oy+settingsTop-10, centered)
+	}
+	if last < len(settingItems) {
+		DrawTextWith(screen, "↓", cx, oy+settingsTop+settingsVisibleRows*settingsRowHeight, centered)
+	}
+	DrawTextWith(screen, tr(msgSettingsHelp), cx, oy+250, centered)
+}
@@ -0,0 +1,147 @@
+package game
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGameSpeedScalesEntities(t *testing.T) {
+	g := New()
+	s := DefaultSettings()
+	s.SpeedPercent = 75
+	g.SetSettings(s)
+
+	d := GetDifficulty(g.level)
+	if g.pacman.Speed != d.PacManSpeed*0.75 {
+		t.Errorf("pac-man speed: got %f, want %f", g.pacman.Speed, d.PacManSpeed*0.75)
+	}
+	for _, ghost := range g.ghosts {
+		if ghost.Speed != d.GhostSpeed*0.75 {
+			t.Errorf("ghost %d speed: got %f, want %f", ghost.ID, ghost.Speed, d.GhostSpeed*0.75)
+		}
+	}
+}
+
+func TestGameSpeedSurvivesRespawn(t *testing.T) {
+	g := New()
+	s := DefaultSettings()
+	s.SpeedPercent = 50
+	g.SetSettings(s)
+
+	g.state = StateDeath
+	g.stateTimer = 1
+	g.updateDeath()
+	if want := GetDifficulty(g.level).PacManSpeed * 0.5; g.pacman.Speed != want {
+		t.Errorf("pac-man speed after respawn: got %f, want %f", g.pacman.Speed, want)
+	}
+}
+
+func TestGameSpeedClamped(t *testing.T) {
+	g := New()
+	s := DefaultSettings()
+	s.SpeedPercent = 10
+	g.SetSettings(s)
+	if g.settings.SpeedPercent != MinSpeedPercent {
+		t.Errorf("speed should clamp to %d, got %d", MinSpeedPercent, g.settings.SpeedPercent)
+	}
+}
+
+func TestSettingsRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "go-pacman", "settings.json")
+	s := DefaultSettings()
+	s.Language = "nb"
+	s.Theme = "colorsafe"
+	s.GhostLetters = true
+	s.SpeedPercent = 80
+	s.MusicVolume = 30
+	s.Muted = true
+	if err := SaveSettings(path, s); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadSettings(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != s {
+		t.Errorf("loaded settings: got %+v, want %+v", got, s)
+	}
+}
+
+func TestFlagSettingsNotSaved(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.json")
+	saved := DefaultSettings()
+	run := saved
+	run.SpeedPercent = 60 // as if given with -speed
+	run.Fullscreen = true
+	g := New()
+	g.SetSettings(run)
+	g.SetSettingsPath(path, saved)
+
+	g.toggleMute()
+	got, err := LoadSettings(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := saved
+	want.Muted = true
+	if got != want {
+		t.Errorf("after muting: got %+v, want %+v", got, want)
+	}
+
+	// Changing a flag's setting in the game saves it
+	g.settings.SpeedPercent = 70
+	g.toggleMute()
+	if got, _ = LoadSettings(path); got.SpeedPercent != 70 || got.Muted || got.Fullscreen {
+		t.Errorf("after changing the speed: got %+v", got)
+	}
+}
+
+func TestLoadSettingsDefaults(t *testing.T) {
+	dir := t.TempDir()
+	got, err := LoadSettings(filepath.Join(dir, "missing.json"))
+	if err != nil || got != DefaultSettings() {
+		t.Errorf("missing file: got %+v, %v; want defaults", got, err)
+	}
+
+	// Fields absent from the file keep their defaults
+	path := filepath.Join(dir, "partial.json")
+	if err := os.WriteFile(path, []byte(`{"ghost_letters": true}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got, err = LoadSettings(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.GhostLetters || got.SpeedPercent != MaxSpeedPercent || got.MasterVolume != MaxVolume {
+		t.Errorf("partial file: got %+v", got)
+	}
+}
+
+func TestVolumeClamped(t *testing.T) {
+	g := New()
+	s := DefaultSettings()
+	s.MasterVolume = 150
+	s.SFXVolume = -10
+	g.SetSettings(s)
+	if g.settings.MasterVolume != MaxVolume || g.settings.SFXVolume != 0 {
+		t.Errorf("volumes should clamp to 0-%d, got master %d, sfx %d", MaxVolume, g.settings.MasterVolume, g.settings.SFXVolume)
+	}
+}
+
+func TestWindowSettingsClamped(t *testing.T) {
+	g := New()
+	s := DefaultSettings()
+	s.WindowScale = 0
+	s.ScreenFilter = "sepia"
+	g.SetSettings(s)
+	if g.settings.WindowScale != 1 || g.settings.ScreenFilter != FilterNone {
+		t.Errorf("got scale %d, filter %q; want 1 and %q", g.settings.WindowScale, g.settings.ScreenFilter, FilterNone)
+	}
+	s.WindowScale = 20
+	s.ScreenFilter = FilterCRT
+	g.SetSettings(s)
+	if g.settings.WindowScale != MaxWindowScale || g.settings.ScreenFilter != FilterCRT {
+		t.Errorf("got scale %d, filter %q; want %d and %q", g.settings.WindowScale, g.settings.ScreenFilter, MaxWindowScale, FilterCRT)
+	}
+}
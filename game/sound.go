@@ -2,153 +2,239 @@ package game
 
 import (
 	"bytes"
-	"encoding/binary"
-	"math"
 
 	"github.com/hajimehoshi/ebiten/v2/audio"
 )
 
-const sampleRate = 44100
+// sfxPoolSize is how many copies of one effect can play at the same time.
+const sfxPoolSize = 3
 
-// SoundManager handles all game sound effects using programmatic audio.
+// channel groups players that share a volume level.
+type channel int
+
+const (
+	channelSFX   channel = iota // chomps, power-up, ghost eaten, fruit, death
+	channelMusic                // jingles and the looping background sounds
+)
+
+// Loop identifies a looping background sound. At most one plays at a time.
+type Loop int
+
+const (
+	LoopNone       Loop = iota
+	LoopSiren           // normal play, pitch set by the siren stage
+	LoopFrightened      // ghosts are frightened
+	LoopEyes            // an eaten ghost is returning to the house
+)
+
+// Mixer owns every audio player and applies the master and channel volumes to them.
+type Mixer struct {
+	context *audio.Context
+	players []mixerPlayer
+	master  float64
+	levels  [2]float64 // per channel, indexed by channel
+	muted   bool
+}
+
+type mixerPlayer struct {
+	player *audio.Player
+	ch     channel
+}
+
+// NewMixer creates a mixer at full volume.
+func NewMixer(context *audio.Context) *Mixer {
+	return &Mixer{context: context, master: 1, levels: [2]float64{1, 1}}
+}
+
+// SetVolume sets the master and channel volumes, each from 0 to 1, and mute.
+func (m *Mixer) SetVolume(master, sfx, music float64, muted bool) {
+	m.master = master
+	m.levels = [2]float64{sfx, music}
+	m.muted = muted
+	for _, mp := range m.players {
+		mp.player.SetVolume(m.volume(mp.ch))
+	}
+}
+
+// volume returns the effective volume of a channel.
+func (m *Mixer) volume(ch channel) float64 {
+	if m.muted {
+		return 0
+	}
+	return m.master * m.levels[ch]
+}
+
+// add registers a player so volume changes reach it.
+func (m *Mixer) add(p *audio.Player, ch channel) *audio.Player {
+	p.SetVolume(m.volume(ch))
+	m.players = append(m.players, mixerPlayer{p, ch})
+	return p
+}
+
+// newPool creates sfxPoolSize players for a one-shot sound.
+func (m *Mixer) newPool(buf []byte, ch channel) *playerPool {
+	pool := &playerPool{}
+	for range sfxPoolSize {
+		pool.players = append(pool.players, m.add(m.context.NewPlayerFromBytes(buf), ch))
+	}
+	return pool
+}
+
+// newLoop creates a player that repeats buf until paused.
+func (m *Mixer) newLoop(buf []byte, ch channel) *audio.Player {
+	p, err := m.context.NewPlayer(audio.NewInfiniteLoop(bytes.NewReader(buf), int64(len(buf))))
+	if err != nil {
+		// Seeking an in-memory reader cannot fail.
+		panic(err)
+	}
+	return m.add(p, ch)
+}
+
+// playerPool is a fixed set of players sharing one buffer, so a sound can
+// overlap itself without creating a player each time it plays.
+type playerPool struct {
+	players []*audio.Player
+	next    int // oldest player, reused when all are busy
+}
+
+// play starts the sound on an idle player, or restarts the oldest one.
+func (pp *playerPool) play() {
+	p := pp.players[pp.next]
+	for _, candidate := range pp.players {
+		if !candidate.IsPlaying() {
+			p = candidate
+			break
+		}
+	}
+	pp.next = (pp.next + 1) % len(pp.players)
+	p.SetPosition(0)
+	p.Play()
+}
+
+// SoundManager is the Sound backend that plays through Ebitengine's audio,
+// using programmatic audio from the synth.
 type SoundManager struct {
-	context    *audio.Context
-	chomp1     []byte
-	chomp2     []byte
+	mixer      *Mixer
+	chomp      [2]*playerPool
 	chompFlip  bool
-	powerUp    []byte
-	ghostEaten []byte
-	death      []byte
-	levelClear []byte
+	powerUp    *playerPool
+	ghostEaten *playerPool
+	fruit      *playerPool
+	death      *playerPool
+	levelClear *playerPool
+	intro      *playerPool
+
+	siren      [SirenStages]*audio.Player
+	frightened *audio.Player
+	eyes       *audio.Player
+	current    *audio.Player // looping player now playing, if any
 }
 
 var audioContext *audio.Context
 
-// NewSoundManager creates and initializes all sound effect buffers.
+// NewSoundManager creates and initializes all sound effect and music players.
 func NewSoundManager() *SoundManager {
 	if audioContext == nil {
-		audioContext = audio.NewContext(sampleRate)
+		audioContext = audio.NewContext(SampleRate)
 	}
-	sm := &SoundManager{
-		context: audioContext,
+	m := NewMixer(audioContext)
+	sm := &SoundManager{mixer: m}
+	sm.chomp[0] = m.newPool(renderSound("chomp1"), channelSFX)
+	sm.chomp[1] = m.newPool(renderSound("chomp2"), channelSFX)
+	sm.powerUp = m.newPool(renderSound("powerup"), channelSFX)
+	sm.ghostEaten = m.newPool(renderSound("ghosteaten"), channelSFX)
+	sm.fruit = m.newPool(renderSound("fruit"), channelSFX)
+	sm.death = m.newPool(renderSound("death"), channelSFX)
+	sm.levelClear = m.newPool(renderSound("levelclear"), channelMusic)
+	sm.intro = m.newPool(renderSound("intro"), channelMusic)
+	for i := range sm.siren {
+		sm.siren[i] = m.newLoop(sirenAtStage(i).Render(SampleRate), channelMusic)
 	}
-	sm.chomp1 = generateSquareWave(260, 0.06, sampleRate)
-	sm.chomp2 = generateSquareWave(390, 0.06, sampleRate)
-	sm.powerUp = generateSweep(800, 200, 0.3, sampleRate)
-	sm.ghostEaten = generateSweep(200, 1200, 0.15, sampleRate)
-	sm.death = generateDeathSound(sampleRate)
-	sm.levelClear = generateArpeggio(sampleRate)
+	sm.frightened = m.newLoop(renderSound("frightened"), channelMusic)
+	sm.eyes = m.newLoop(renderSound("eyes"), channelMusic)
 	return sm
 }
 
+// ApplySettings sets the mixer volumes from the player's settings.
+func (sm *SoundManager) ApplySettings(s Settings) {
+	sm.mixer.SetVolume(
+		float64(s.MasterVolume)/100,
+		float64(s.SFXVolume)/100,
+		float64(s.MusicVolume)/100,
+		s.Muted,
+	)
+}
+
 // PlayChomp plays the alternating dot-eating sound.
 func (sm *SoundManager) PlayChomp() {
 	if sm.chompFlip {
-		sm.playBuffer(sm.chomp1)
+		sm.chomp[0].play()
 	} else {
-		sm.playBuffer(sm.chomp2)
+		sm.chomp[1].play()
 	}
 	sm.chompFlip = !sm.chompFlip
 }
 
 // PlayPowerUp plays the power pellet sound.
 func (sm *SoundManager) PlayPowerUp() {
-	sm.playBuffer(sm.powerUp)
+	sm.powerUp.play()
 }
 
 // PlayGhostEaten plays the ghost-eating chirp.
 func (sm *SoundManager) PlayGhostEaten() {
-	sm.playBuffer(sm.ghostEaten)
+	sm.ghostEaten.play()
+}
+
+// PlayFruit plays the bonus fruit sound.
+func (sm *SoundManager) PlayFruit() {
+	sm.fruit.play()
 }
 
 // PlayDeath plays the Pac-Man death sound.
 func (sm *SoundManager) PlayDeath() {
-	sm.playBuffer(sm.death)
+	sm.death.play()
 }
 
 // PlayLevelClear plays the level-clear arpeggio.
 func (sm *SoundManager) PlayLevelClear() {
-	sm.playBuffer(sm.levelClear)
-}
-
-func (sm *SoundManager) playBuffer(buf []byte) {
-	player := sm.context.NewPlayerFromBytes(buf)
-	player.Play()
-}
-
-// generateSquareWave creates a square wave at the given frequency and duration.
-// Output is 16-bit signed little-endian stereo PCM.
-func generateSquareWave(freq, duration float64, sr int) []byte {
-	numSamples := int(duration * float64(sr))
-	buf := new(bytes.Buffer)
-	for i := 0; i < numSamples; i++ {
-		t := float64(i) / float64(sr)
-		var val int16
-		if math.Sin(2*math.Pi*freq*t) >= 0 {
-			val = 8000
-		} else {
-			val = -8000
-		}
-		binary.Write(buf, binary.LittleEndian, val) // left
-		binary.Write(buf, binary.LittleEndian, val) // right
+	sm.levelClear.play()
+}
+
+// PlayIntro plays the jingle heard while READY! is shown at the start of a game.
+func (sm *SoundManager) PlayIntro() {
+	sm.intro.play()
+}
+
+// SetLoop switches the background loop. stage picks the siren pitch and is
+// ignored for other loops. Calling it again with the same loop keeps it playing.
+func (sm *SoundManager) SetLoop(loop Loop, stage int) {
+	var next *audio.Player
+	switch loop {
+	case LoopSiren:
+		next = sm.siren[min(max(stage, 0), SirenStages-1)]
+	case LoopFrightened:
+		next = sm.frightened
+	case LoopEyes:
+		next = sm.eyes
 	}
-	return buf.Bytes()
-}
-
-// generateSweep creates a frequency sweep from startFreq to endFreq.
-func generateSweep(startFreq, endFreq, duration float64, sr int) []byte {
-	numSamples := int(duration * float64(sr))
-	buf := new(bytes.Buffer)
-	phase := 0.0
-	for i := 0; i < numSamples; i++ {
-		t := float64(i) / float64(numSamples)
-		freq := startFreq + (endFreq-startFreq)*t
-		phase += 2 * math.Pi * freq / float64(sr)
-		var val int16
-		if math.Sin(phase) >= 0 {
-			val = 8000
-		} else {
-			val = -8000
-		}
-		binary.Write(buf, binary.LittleEndian, val)
-		binary.Write(buf, binary.LittleEndian, val)
+	if next == sm.current {
+		return
 	}
-	return buf.Bytes()
-}
-
-// generateDeathSound creates the descending warble death sound.
-func generateDeathSound(sr int) []byte {
-	duration := 1.5
-	numSamples := int(duration * float64(sr))
-	buf := new(bytes.Buffer)
-	phase := 0.0
-	for i := 0; i < numSamples; i++ {
-		t := float64(i) / float64(numSamples)
-		baseFreq := 800 - 700*t // 800 -> 100
-		warble := math.Sin(2*math.Pi*6*t) * 50
-		freq := baseFreq + warble
-		phase += 2 * math.Pi * freq / float64(sr)
-		val := int16(math.Sin(phase) * 6000)
-		binary.Write(buf, binary.LittleEndian, val)
-		binary.Write(buf, binary.LittleEndian, val)
+	if sm.current != nil {
+		sm.current.Pause()
 	}
-	return buf.Bytes()
-}
-
-// generateArpeggio creates an ascending arpeggio for level clear.
-func generateArpeggio(sr int) []byte {
-	// C5, E5, G5, C6
-	notes := []float64{523.25, 659.25, 783.99, 1046.50}
-	noteDuration := 0.125
-	buf := new(bytes.Buffer)
-	for _, freq := range notes {
-		numSamples := int(noteDuration * float64(sr))
-		for i := 0; i < numSamples; i++ {
-			t := float64(i) / float64(sr)
-			val := int16(math.Sin(2*math.Pi*freq*t) * 6000)
-			binary.Write(buf, binary.LittleEndian, val)
-			binary.Write(buf, binary.LittleEndian, val)
-		}
+	sm.current = next
+	if next != nil {
+		next.Play()
+	}
+}
+
+// sirenStage maps the dots left in the maze to a siren stage, from 0 with a
+// full maze up to SirenStages-1 near the end of the level.
+func sirenStage(remaining, total int) int {
+	if total <= 0 {
+		return 0
 	}
-	return buf.Bytes()
+	eaten := total - remaining
+	return min(max(eaten*SirenStages/total, 0), SirenStages-1)
 }
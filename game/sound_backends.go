@@ -0,0 +1,119 @@
+package game
+
+import "fmt"
+
+// Sound plays the game's sound effects and music. SoundManager plays through
+// Ebitengine's audio; NoSound and SoundRecorder need no audio device, for
+// tests and headless runs.
+type Sound interface {
+	PlayChomp()
+	PlayPowerUp()
+	PlayGhostEaten()
+	PlayFruit()
+	PlayDeath()
+	PlayLevelClear()
+	PlayIntro()
+	// SetLoop switches the background loop; see SoundManager.SetLoop.
+	SetLoop(loop Loop, stage int)
+	// ApplySettings sets the volumes and mute from the player's settings.
+	ApplySettings(s Settings)
+}
+
+var (
+	_ Sound = (*SoundManager)(nil)
+	_ Sound = NoSound{}
+	_ Sound = (*SoundRecorder)(nil)
+)
+
+// SetSound replaces the game's sound backend. New games start with NoSound.
+func (g *Game) SetSound(s Sound) {
+	g.sound = s
+	s.ApplySettings(g.settings)
+}
+
+// String returns the loop's name, as used in SoundRecorder events.
+func (l Loop) String() string {
+	switch l {
+	case LoopSiren:
+		return "siren"
+	case LoopFrightened:
+		return "frightened"
+	case LoopEyes:
+		return "eyes"
+	default:
+		return "none"
+	}
+}
+
+// NoSound is a Sound that plays nothing.
+type NoSound struct{}
+
+func (NoSound) PlayChomp()             {}
+func (NoSound) PlayPowerUp()           {}
+func (NoSound) PlayGhostEaten()        {}
+func (NoSound) PlayFruit()             {}
+func (NoSound) PlayDeath()             {}
+func (NoSound) PlayLevelClear()        {}
+func (NoSound) PlayIntro()             {}
+func (NoSound) SetLoop(Loop, int)      {}
+func (NoSound) ApplySettings(Settings) {}
+
+// SoundEvent is one sound recorded by a SoundRecorder. Effect is the name of
+// the effect ("chomp", "powerup", "ghosteaten", "fruit", "death",
+// "levelclear", "intro") or "loop:" plus the loop name when the background loop changes.
+type SoundEvent struct {
+	Tick   int
+	Effect string
+}
+
+func (e SoundEvent) String() string {
+	return fmt.Sprintf("%d %s", e.Tick, e.Effect)
+}
+
+// SoundRecorder is a Sound that logs which effect played on which tick
+// instead of playing it.
+type SoundRecorder struct {
+	Events []SoundEvent
+
+	clock func() int
+	loop  Loop
+}
+
+// NewSoundRecorder returns a recorder that stamps events with the tick
+// returned by clock, e.g. the game's tick count.
+func NewSoundRecorder(clock func() int) *SoundRecorder {
+	return &SoundRecorder{clock: clock}
+}
+
+func (r *SoundRecorder) record(effect string) {
+	r.Events = append(r.Events, SoundEvent{Tick: r.clock(), Effect: effect})
+}
+
+func (r *SoundRecorder) PlayChomp()      { r.record("chomp") }
+func (r *SoundRecorder) PlayPowerUp()    { r.record("powerup") }
+func (r *SoundRecorder) PlayGhostEaten() { r.record("ghosteaten") }
+func (r *SoundRecorder) PlayFruit()      { r.record("fruit") }
+func (r *SoundRecorder) PlayDeath()      { r.record("death") }
+func (r *SoundRecorder) PlayLevelClear() { r.record("levelclear") }
+func (r *SoundRecorder) PlayIntro()      { r.record("intro") }
+
+// SetLoop records a loop change. Siren stage changes are not recorded.
+func (r *SoundRecorder) SetLoop(loop Loop, stage int) {
+	if loop != r.loop {
+		r.loop = loop
+		r.record("loop:" + loop.String())
+	}
+}
+
+func (r *SoundRecorder) ApplySettings(Settings) {}
+
+// Count returns how many times effect was recorded.
+func (r *SoundRecorder) Count(effect string) int {
+	n := 0
+	for _, e := range r.Events {
+		if e.Effect == effect {
+			n++
+		}
+	}
+	return n
+}
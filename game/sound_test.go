@@ -0,0 +1,102 @@
+package game
+
+import "testing"
+
+func TestSirenStage(t *testing.T) {
+	const total = 100
+	tests := []struct {
+		remaining int
+		want      int
+	}{
+		{100, 0},
+		{81, 0},
+		{80, 1},
+		{40, 3},
+		{1, SirenStages - 1},
+		{0, SirenStages - 1},
+	}
+	for _, tt := range tests {
+		if got := sirenStage(tt.remaining, total); got != tt.want {
+			t.Errorf("sirenStage(%d, %d): got %d, want %d", tt.remaining, total, got, tt.want)
+		}
+	}
+}
+
+func TestBackgroundLoop(t *testing.T) {
+	g := New()
+	if got := g.backgroundLoop(); got != LoopNone {
+		t.Errorf("title screen: got %d, want LoopNone", got)
+	}
+
+	g.state = StatePlaying
+	if got := g.backgroundLoop(); got != LoopSiren {
+		t.Errorf("playing: got %d, want LoopSiren", got)
+	}
+
+	g.triggerFrightenedMode()
+	if got := g.backgroundLoop(); got != LoopFrightened {
+		t.Errorf("frightened: got %d, want LoopFrightened", got)
+	}
+
+	g.ghosts[0].Mode = GhostEaten
+	if got := g.backgroundLoop(); got != LoopEyes {
+		t.Errorf("eyes returning: got %d, want LoopEyes", got)
+	}
+}
+
+func TestPowerPelletPlaysPowerUp(t *testing.T) {
+	g := New()
+	rec := NewSoundRecorder(func() int { return g.tickCount })
+	g.SetSound(rec)
+	g.tickCount = 42
+
+	// Move pacman to a power pellet position (1,3)
+	g.pacman.X = float64(1*TileSize + TileSize/2)
+	g.pacman.Y = float64(3*TileSize + TileSize/2)
+	g.checkDotConsumption()
+
+	want := []SoundEvent{{Tick: 42, Effect: "powerup"}}
+	if len(rec.Events) != 1 || rec.Events[0] != want[0] {
+		t.Errorf("events: got %v, want %v", rec.Events, want)
+	}
+}
+
+func TestGhostCollisionSounds(t *testing.T) {
+	g := New()
+	rec := NewSoundRecorder(func() int { return g.tickCount })
+	g.SetSound(rec)
+
+	ghost := g.ghosts[0]
+	ghost.InHouse = false
+	ghost.X, ghost.Y = g.pacman.X, g.pacman.Y
+
+	ghost.Mode = GhostFrightened
+	g.checkGhostCollisions()
+	if rec.Count("ghosteaten") != 1 {
+		t.Errorf("eating a frightened ghost should play ghosteaten, got %v", rec.Events)
+	}
+
+	ghost.Mode = GhostChase
+	g.checkGhostCollisions()
+	if rec.Count("death") != 1 {
+		t.Errorf("touching a chasing ghost should play death, got %v", rec.Events)
+	}
+}
+
+func TestRecorderLogsLoopChanges(t *testing.T) {
+	tick := 0
+	rec := NewSoundRecorder(func() int { return tick })
+	for ; tick < 5; tick++ {
+		rec.SetLoop(LoopSiren, tick) // stage changes alone are not logged
+	}
+	rec.SetLoop(LoopFrightened, 0)
+	want := []SoundEvent{{0, "loop:siren"}, {5, "loop:frightened"}}
+	if len(rec.Events) != len(want) {
+		t.Fatalf("events: got %v, want %v", rec.Events, want)
+	}
+	for i := range want {
+		if rec.Events[i] != want[i] {
+			t.Errorf("event %d: got %v, want %v", i, rec.Events[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package game
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SampleRate is the rate all sounds are rendered and played at.
+const SampleRate = 44100
+
+// SirenStages is the number of siren pitches. The siren moves up one stage as
+// each further share of the maze's dots is eaten.
+const SirenStages = 5
+
+//go:embed sounds/*.seq
+var soundFiles embed.FS
+
+// soundBank maps a sound name (its file name without .seq) to its sequence.
+var soundBank = loadSoundBank()
+
+// loadSoundBank parses every embedded sound file, panicking on a bad one as
+// loadCatalogs does. A sequence that fails to parse would otherwise only show
+// as a missing sound the first time the game plays it.
+func loadSoundBank() map[string]*Sequence {
+	entries, err := soundFiles.ReadDir("sounds")
+	if err != nil {
+		panic(err)
+	}
+	bank := make(map[string]*Sequence)
+	for _, e := range entries {
+		f, err := soundFiles.Open(path.Join("sounds", e.Name()))
+		if err != nil {
+			panic(err)
+		}
+		seq, err := ParseSequence(f)
+		f.Close()
+		if err != nil {
+			panic(fmt.Sprintf("sound %s: %v", e.Name(), err))
+		}
+		bank[strings.TrimSuffix(e.Name(), ".seq")] = seq
+	}
+	return bank
+}
+
+// soundSequence returns the named sequence from the sound bank.
+func soundSequence(name string) *Sequence {
+	seq, ok := soundBank[name]
+	if !ok {
+		panic(fmt.Sprintf("no sound %q in sounds/", name))
+	}
+	return seq
+}
+
+// renderSound renders the named sequence from sounds/ at the game's sample rate.
+func renderSound(name string) []byte {
+	return soundSequence(name).Render(SampleRate)
+}
+
+// sirenAtStage returns the siren sequence for a siren stage. Each stage raises
+// the pitch by a sixth of the base pitch and shortens the cycle by 0.04 seconds.
+func sirenAtStage(stage int) *Sequence {
+	seq := soundSequence("siren")
+	cycle := seq.Duration()
+	pitch := 1 + float64(stage)/6
+	speed := cycle / max(cycle-0.04*float64(stage), 0.1)
+	return seq.Scaled(pitch, speed)
+}
+
+// sirenStagePrefix names the higher siren stages in SoundNames: "siren-stage1"
+// and up. Stage 0 is plain "siren".
+const sirenStagePrefix = "siren-stage"
+
+// SoundNames returns the name of every sound the game plays, sorted: the
+// files in sounds/ plus each higher siren stage.
+func SoundNames() []string {
+	names := make([]string, 0, len(soundBank)+SirenStages-1)
+	for name := range soundBank {
+		names = append(names, name)
+	}
+	for stage := 1; stage < SirenStages; stage++ {
+		names = append(names, sirenStagePrefix+strconv.Itoa(stage))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RenderSound renders a sound from SoundNames as 16-bit signed little-endian
+// stereo PCM at the game's sample rate.
+func RenderSound(name string) ([]byte, error) {
+	if s, ok := strings.CutPrefix(name, sirenStagePrefix); ok {
+		stage, err := strconv.Atoi(s)
+		if err != nil || stage < 1 || stage >= SirenStages {
+			return nil, fmt.Errorf("no siren stage %q", s)
+		}
+		return sirenAtStage(stage).Render(SampleRate), nil
+	}
+	seq, ok := soundBank[name]
+	if !ok {
+		return nil, fmt.Errorf("no sound %q", name)
+	}
+	return seq.Render(SampleRate), nil
+}
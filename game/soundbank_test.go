@@ -0,0 +1,112 @@
+package game
+
+import (
+	"bytes"
+	"encoding/binary"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// updateGolden rewrites golden files from the current output instead of
+// comparing against them: go test ./game -update
+var updateGolden = flag.Bool("update", false, "rewrite golden files in testdata")
+
+func TestWriteWAV(t *testing.T) {
+	var buf bytes.Buffer
+	pcm := []byte{1, 2, 3, 4, 5, 6, 7, 8} // two stereo frames
+	if err := WriteWAV(&buf, pcm, 22050); err != nil {
+		t.Fatal(err)
+	}
+	b := buf.Bytes()
+	if len(b) != 44+len(pcm) {
+		t.Fatalf("file size: got %d, want %d", len(b), 44+len(pcm))
+	}
+	checks := []struct {
+		name      string
+		off, size int
+		want      uint32
+	}{
+		{"RIFF size", 4, 4, 36 + 8},
+		{"format", 20, 2, 1},
+		{"channels", 22, 2, 2},
+		{"sample rate", 24, 4, 22050},
+		{"byte rate", 28, 4, 22050 * 4},
+		{"bits per sample", 34, 2, 16},
+		{"data size", 40, 4, 8},
+	}
+	for _, c := range checks {
+		var got uint32
+		if c.size == 2 {
+			got = uint32(binary.LittleEndian.Uint16(b[c.off:]))
+		} else {
+			got = binary.LittleEndian.Uint32(b[c.off:])
+		}
+		if got != c.want {
+			t.Errorf("%s: got %d, want %d", c.name, got, c.want)
+		}
+	}
+	if string(b[0:4]) != "RIFF" || string(b[8:12]) != "WAVE" || string(b[36:40]) != "data" {
+		t.Error("missing RIFF, WAVE or data tag")
+	}
+	if !bytes.Equal(b[44:], pcm) {
+		t.Error("PCM data should follow the header unchanged")
+	}
+}
+
+// TestSoundGolden renders every sound and compares it with the WAV files in
+// testdata/sounds, so changes to the synth or the sound files are noticed.
+// After an intended change, rerun with -update and listen to the new files.
+func TestSoundGolden(t *testing.T) {
+	for _, name := range SoundNames() {
+		t.Run(name, func(t *testing.T) {
+			pcm, err := RenderSound(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			path := filepath.Join("testdata", "sounds", name+".wav")
+			if *updateGolden {
+				var buf bytes.Buffer
+				if err := WriteWAV(&buf, pcm, SampleRate); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			golden, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("%v (run with -update to create it)", err)
+			}
+			comparePCM(t, pcm, golden[44:])
+		})
+	}
+}
+
+// comparePCM reports whether two 16-bit PCM buffers match. Floating point can
+// round differently between CPU architectures (fused multiply-add), which may
+// nudge a sample by one step or move a square wave edge by one sample, so a
+// few differing samples are tolerated.
+func comparePCM(t *testing.T, got, want []byte) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("length: got %d bytes, want %d", len(got), len(want))
+	}
+	samples := len(got) / 2
+	off := 0
+	for i := 0; i < samples; i++ {
+		a := int(int16(binary.LittleEndian.Uint16(got[i*2:])))
+		b := int(int16(binary.LittleEndian.Uint16(want[i*2:])))
+		if a-b > 2 || b-a > 2 {
+			off++
+		}
+	}
+	if off > samples/1000 {
+		t.Errorf("%d of %d samples differ from the golden file", off, samples)
+	}
+}
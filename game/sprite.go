@@ -1,6 +1,7 @@
 package game
 
 import (
+	"image"
 	"image/color"
 	"math"
 
@@ -13,34 +14,56 @@ const PacManSpriteSize = 13
 // GhostSpriteSize is the width/height of ghost sprites in pixels.
 const GhostSpriteSize = 13
 
+// GhostAnimFrames is the number of skirt animation frames per ghost sprite.
+const GhostAnimFrames = 2
+
 // Sprites holds pre-generated tile images for maze rendering.
 type Sprites struct {
-	Wall         *ebiten.Image
-	Dot          *ebiten.Image
-	PowerPellet  *ebiten.Image
-	Empty        *ebiten.Image
-	GhostDoor    *ebiten.Image
-	PacManFrames [3]*ebiten.Image    // closed, half-open, full-open
-	PacManDeath  [11]*ebiten.Image   // death animation frames (progressively larger mouth)
-	GhostSprites [4]*ebiten.Image    // one per ghost ID (Blinky, Pinky, Inky, Clyde)
-	GhostFrightened *ebiten.Image    // blue frightened ghost
-	GhostEyes    *ebiten.Image       // just eyes for eaten ghost
+	Dot                  *ebiten.Image
+	PowerPellet          *ebiten.Image
+	Empty                *ebiten.Image
+	GhostDoor            *ebiten.Image
+	PacManFrames         [3]*ebiten.Image                     // closed, half-open, full-open
+	PacManDeath          [11]*ebiten.Image                    // death animation frames (progressively larger mouth)
+	GhostSprites         [4][5][GhostAnimFrames]*ebiten.Image // [ghost ID][Direction][skirt frame]
+	GhostFrightened      [GhostAnimFrames]*ebiten.Image       // blue frightened ghost
+	GhostFrightenedFlash [GhostAnimFrames]*ebiten.Image       // white frightened ghost shown while flashing
+	GhostEyes            [5]*ebiten.Image                     // just eyes for eaten ghost, indexed by Direction
+	GhostLetters         [4]*ebiten.Image                     // initial drawn over each ghost's body (accessibility)
+	Fruits               [FruitKey]*ebiten.Image              // bonus fruit, indexed by Fruit-1
 }
 
 // sprites is the package-level sprite cache, initialized by InitSprites.
 var sprites *Sprites
 
-// InitSprites generates all tile sprites and stores them in the package-level cache.
+// InitSprites generates all tile sprites in the active theme's palette and stores
+// them in the package-level cache.
 func InitSprites() {
-	ghostColors := [4]color.RGBA{
-		{R: 0xFF, G: 0x00, B: 0x00, A: 0xFF}, // Blinky - red
-		{R: 0xFF, G: 0xB8, B: 0xFF, A: 0xFF}, // Pinky - pink
-		{R: 0x00, G: 0xFF, B: 0xFF, A: 0xFF}, // Inky - cyan
-		{R: 0xFF, G: 0xB8, B: 0x52, A: 0xFF}, // Clyde - orange
+	if sprites != nil {
+		for _, slot := range spriteSlots(sprites) {
+			forgetImage(*slot.img)
+		}
+	}
+	ghostColors := theme.Palette.Ghosts
+	var ghostSprites [4][5][GhostAnimFrames]*ebiten.Image
+	var ghostEyes [5]*ebiten.Image
+	var frightened, frightenedFlash [GhostAnimFrames]*ebiten.Image
+	for dir := DirNone; dir <= DirRight; dir++ {
+		for i := 0; i < 4; i++ {
+			for f := 0; f < GhostAnimFrames; f++ {
+				ghostSprites[i][dir][f] = GenerateGhostSprite(ghostColors[i], dir, f)
+			}
+		}
+		ghostEyes[dir] = GenerateGhostEyes(dir)
+	}
+	for f := 0; f < GhostAnimFrames; f++ {
+		frightened[f] = GenerateGhostFrightened(f)
+		frightenedFlash[f] = GenerateGhostFrightenedFlash(f)
 	}
-	var ghostSprites [4]*ebiten.Image
+
+	var ghostLetters [4]*ebiten.Image
 	for i := 0; i < 4; i++ {
-		ghostSprites[i] = GenerateGhostSprite(ghostColors[i])
+		ghostLetters[i] = GenerateGhostLetter(GhostID(i))
 	}
 
 	var deathFrames [11]*ebiten.Image
@@ -48,8 +71,12 @@ func InitSprites() {
 		deathFrames[i] = GeneratePacManDeathFrame(i)
 	}
 
+	var fruits [FruitKey]*ebiten.Image
+	for i := range fruits {
+		fruits[i] = GenerateFruitSprite(Fruit(i + 1))
+	}
+
 	sprites = &Sprites{
-		Wall:        GenerateWallTile(),
 		Dot:         GenerateDotSprite(),
 		PowerPellet: GeneratePowerPelletSprite(),
 		Empty:       GenerateEmptyTile(),
@@ -59,70 +86,54 @@ func InitSprites() {
 			GeneratePacManFrame(1),
 			GeneratePacManFrame(2),
 		},
-		PacManDeath:     deathFrames,
-		GhostSprites:    ghostSprites,
-		GhostFrightened: GenerateGhostFrightened(),
-		GhostEyes:       GenerateGhostEyes(),
-	}
-}
-
-// GenerateWallTile returns an 8x8 blue tile with a 1px black border.
-func GenerateWallTile() *ebiten.Image {
-	img := ebiten.NewImage(TileSize, TileSize)
-	blue := color.RGBA{R: 0x21, G: 0x21, B: 0xDE, A: 0xFF}
-	black := color.RGBA{R: 0x00, G: 0x00, B: 0x00, A: 0xFF}
-
-	for y := 0; y < TileSize; y++ {
-		for x := 0; x < TileSize; x++ {
-			if x == 0 || x == TileSize-1 || y == 0 || y == TileSize-1 {
-				img.Set(x, y, black)
-			} else {
-				img.Set(x, y, blue)
-			}
-		}
+		PacManDeath:          deathFrames,
+		GhostSprites:         ghostSprites,
+		GhostFrightened:      frightened,
+		GhostFrightenedFlash: frightenedFlash,
+		GhostEyes:            ghostEyes,
+		GhostLetters:         ghostLetters,
+		Fruits:               fruits,
 	}
-	return img
 }
 
 // GenerateDotSprite returns an 8x8 image with a 2x2 white square centered (pixels 3-4, 3-4).
 func GenerateDotSprite() *ebiten.Image {
-	img := ebiten.NewImage(TileSize, TileSize)
-	white := color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF}
+	img := newRGBA(TileSize, TileSize)
+	white := theme.Palette.Dot
 
 	for y := 3; y <= 4; y++ {
 		for x := 3; x <= 4; x++ {
 			img.Set(x, y, white)
 		}
 	}
-	return img
+	return newImage(img)
 }
 
 // GeneratePowerPelletSprite returns an 8x8 image with a 6x6 white square centered (pixels 1-6, 1-6).
 func GeneratePowerPelletSprite() *ebiten.Image {
-	img := ebiten.NewImage(TileSize, TileSize)
-	white := color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF}
+	img := newRGBA(TileSize, TileSize)
+	white := theme.Palette.Dot
 
 	for y := 1; y <= 6; y++ {
 		for x := 1; x <= 6; x++ {
 			img.Set(x, y, white)
 		}
 	}
-	return img
+	return newImage(img)
 }
 
 // GenerateEmptyTile returns an 8x8 fully black image.
 func GenerateEmptyTile() *ebiten.Image {
-	img := ebiten.NewImage(TileSize, TileSize)
-	// ebiten.NewImage is already initialized to transparent black.
-	// Fill with opaque black to be explicit.
-	img.Fill(color.RGBA{R: 0x00, G: 0x00, B: 0x00, A: 0xFF})
-	return img
+	img := newRGBA(TileSize, TileSize)
+	// A new image is transparent; fill it with the background to be explicit.
+	fillRGBA(img, theme.Palette.Background)
+	return newImage(img)
 }
 
 // GenerateGhostDoorTile returns an 8x8 image with a pink horizontal bar in the middle (2 pixels tall, centered).
 func GenerateGhostDoorTile() *ebiten.Image {
-	img := ebiten.NewImage(TileSize, TileSize)
-	pink := color.RGBA{R: 0xFF, G: 0xB8, B: 0xFF, A: 0xFF}
+	img := newRGBA(TileSize, TileSize)
+	pink := theme.Palette.GhostDoor
 
 	// 2 pixels tall, centered vertically: rows 3 and 4
 	for y := 3; y <= 4; y++ {
@@ -130,7 +141,7 @@ func GenerateGhostDoorTile() *ebiten.Image {
 			img.Set(x, y, pink)
 		}
 	}
-	return img
+	return newImage(img)
 }
 
 // GeneratePacManFrame generates a 13x13 Pac-Man sprite frame.
@@ -143,8 +154,8 @@ func GeneratePacManFrame(frame int) *ebiten.Image {
 	const cx, cy = 6, 6 // center
 	const radius = 6
 
-	img := ebiten.NewImage(size, size)
-	yellow := color.RGBA{R: 0xFF, G: 0xFF, B: 0x00, A: 0xFF}
+	img := newRGBA(size, size)
+	yellow := theme.Palette.PacMan
 
 	// Determine mouth half-angle in radians based on frame.
 	var mouthHalfAngle float64
@@ -186,14 +197,26 @@ func GeneratePacManFrame(frame int) *ebiten.Image {
 		}
 	}
 
-	return img
+	return newImage(img)
 }
 
 // GenerateGhostSprite generates a 13x13 ghost sprite with the given body color.
-// Shape: rounded top (semicircle), flat sides, wavy bottom (3 bumps), with eyes.
-func GenerateGhostSprite(bodyColor color.RGBA) *ebiten.Image {
+// Shape: rounded top (semicircle), flat sides, wavy bottom, with eyes looking in dir.
+// frame 0 has 3 skirt bumps, frame 1 has 4 bumps shifted by half a bump so the
+// skirt appears to ripple when the frames alternate.
+func GenerateGhostSprite(bodyColor color.RGBA, dir Direction, frame int) *ebiten.Image {
+	return newImage(ghostRGBA(bodyColor, dir, frame))
+}
+
+// ghostRGBA draws the ghost sprite for GenerateGhostSprite.
+func ghostRGBA(bodyColor color.RGBA, dir Direction, frame int) *image.RGBA {
 	const size = GhostSpriteSize
-	img := ebiten.NewImage(size, size)
+	img := newRGBA(size, size)
+
+	bumps := []int{2, 6, 10}
+	if frame%GhostAnimFrames == 1 {
+		bumps = []int{0, 4, 8, 12}
+	}
 
 	for y := 0; y < size; y++ {
 		for x := 0; x < size; x++ {
@@ -210,9 +233,9 @@ func GenerateGhostSprite(bodyColor color.RGBA) *ebiten.Image {
 					img.Set(x, y, bodyColor)
 				}
 			} else {
-				// Bottom: wavy edge with 3 bumps
+				// Bottom: wavy edge
 				bump := false
-				for _, cx := range []int{2, 6, 10} {
+				for _, cx := range bumps {
 					dx := x - cx
 					dy := y - 10
 					if dx*dx+dy*dy <= 4 {
@@ -227,14 +250,29 @@ func GenerateGhostSprite(bodyColor color.RGBA) *ebiten.Image {
 		}
 	}
 
-	drawGhostEyesOn(img)
+	drawGhostEyesOn(img, dir)
 	return img
 }
 
-// drawGhostEyesOn draws two eyes with pupils on the given image.
-func drawGhostEyesOn(img *ebiten.Image) {
-	white := color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF}
-	blue := color.RGBA{R: 0x21, G: 0x21, B: 0xFF, A: 0xFF}
+// ghostPupilOffset returns the pupil offset from the eye center for a direction.
+// DirNone looks right, matching a ghost's default facing.
+func ghostPupilOffset(dir Direction) (int, int) {
+	switch dir {
+	case DirUp:
+		return 0, -1
+	case DirDown:
+		return 0, 1
+	case DirLeft:
+		return -1, 0
+	}
+	return 1, 0
+}
+
+// drawGhostEyesOn draws two eyes with pupils looking in dir on the given image.
+func drawGhostEyesOn(img *image.RGBA, dir Direction) {
+	white := theme.Palette.Eyes
+	blue := theme.Palette.Pupils
+	px, py := ghostPupilOffset(dir)
 
 	for _, ex := range []int{4, 8} {
 		for y := 0; y < GhostSpriteSize; y++ {
@@ -246,20 +284,41 @@ func drawGhostEyesOn(img *ebiten.Image) {
 				}
 			}
 		}
-		// Pupil: blue dot (looking forward/right)
-		img.Set(ex+1, 4, blue)
-		img.Set(ex+1, 5, blue)
+		// Pupil: 2-pixel blue dot pushed toward the edge of the eye
+		if py == 0 {
+			img.Set(ex+px, 4, blue)
+			img.Set(ex+px, 5, blue)
+		} else {
+			img.Set(ex, 4+py, blue)
+			img.Set(ex, 4+2*py, blue)
+		}
 	}
 }
 
-// GenerateGhostFrightened generates the blue frightened ghost sprite.
-func GenerateGhostFrightened() *ebiten.Image {
-	blue := color.RGBA{R: 0x21, G: 0x21, B: 0xFF, A: 0xFF}
-	img := GenerateGhostSprite(blue)
+// GenerateGhostFrightened generates the blue frightened ghost sprite for a skirt frame.
+func GenerateGhostFrightened(frame int) *ebiten.Image {
+	return generateFrightenedGhost(theme.Palette.Frightened, theme.Palette.FrightenedFace, frame)
+}
+
+// GenerateGhostFrightenedFlash generates the white sprite frightened ghosts
+// alternate with when frightened mode is about to end.
+func GenerateGhostFrightenedFlash(frame int) *ebiten.Image {
+	return generateFrightenedGhost(theme.Palette.Flash, theme.Palette.FlashFace, frame)
+}
 
-	white := color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF}
-	img.Set(4, 4, white)
-	img.Set(8, 4, white)
+// generateFrightenedGhost draws a frightened ghost body with the given face color
+// for the eyes and wavy mouth.
+func generateFrightenedGhost(body, face color.RGBA, frame int) *ebiten.Image {
+	img := ghostRGBA(body, DirNone, frame)
+
+	// Replace the regular eyes with small dots
+	for y := 2; y <= 6; y++ {
+		for x := 2; x <= 10; x++ {
+			img.Set(x, y, body)
+		}
+	}
+	img.Set(4, 4, face)
+	img.Set(8, 4, face)
 
 	// Wavy mouth
 	for x := 2; x <= 10; x++ {
@@ -267,16 +326,40 @@ func GenerateGhostFrightened() *ebiten.Image {
 		if x%2 == 0 {
 			yOff = 9
 		}
-		img.Set(x, yOff, white)
+		img.Set(x, yOff, face)
 	}
-	return img
+	return newImage(img)
 }
 
-// GenerateGhostEyes generates just the eyes sprite for eaten ghosts.
-func GenerateGhostEyes() *ebiten.Image {
-	img := ebiten.NewImage(GhostSpriteSize, GhostSpriteSize)
-	drawGhostEyesOn(img)
-	return img
+// GenerateGhostEyes generates just the eyes sprite for eaten ghosts, looking in dir.
+func GenerateGhostEyes(dir Direction) *ebiten.Image {
+	img := newRGBA(GhostSpriteSize, GhostSpriteSize)
+	drawGhostEyesOn(img, dir)
+	return newImage(img)
+}
+
+// ghostLetterGlyphs are 3x5 initials (B, P, I, C) for the ghost letter overlays.
+// Each row's lower 3 bits are the pixels.
+var ghostLetterGlyphs = [4][5]uint8{
+	{0x6, 0x5, 0x6, 0x5, 0x6}, // B
+	{0x6, 0x5, 0x6, 0x4, 0x4}, // P
+	{0x7, 0x2, 0x2, 0x2, 0x7}, // I
+	{0x3, 0x4, 0x4, 0x4, 0x3}, // C
+}
+
+// GenerateGhostLetter generates a 13x13 overlay with the ghost's initial cut out
+// of the lower body in the background color, below the eyes.
+func GenerateGhostLetter(id GhostID) *ebiten.Image {
+	img := newRGBA(GhostSpriteSize, GhostSpriteSize)
+	glyph := ghostLetterGlyphs[id]
+	for row := 0; row < 5; row++ {
+		for col := 0; col < 3; col++ {
+			if glyph[row]&(1<<(2-col)) != 0 {
+				img.Set(5+col, 7+row, theme.Palette.Background)
+			}
+		}
+	}
+	return newImage(img)
 }
 
 // GeneratePacManDeathFrame generates a death animation frame.
@@ -286,15 +369,15 @@ func GeneratePacManDeathFrame(frame int) *ebiten.Image {
 	const cx, cy = 6, 6
 	const radius = 6
 
-	img := ebiten.NewImage(size, size)
-	yellow := color.RGBA{R: 0xFF, G: 0xFF, B: 0x00, A: 0xFF}
+	img := newRGBA(size, size)
+	yellow := theme.Palette.PacMan
 
 	// Mouth half-angle: from 30 degrees (frame 0) to 180 degrees (frame 10)
 	mouthHalfAngle := (30.0 + float64(frame)*150.0/10.0) * math.Pi / 180.0
 
 	// Last frame is empty (fully disappeared)
 	if frame == 10 {
-		return img
+		return newImage(img)
 	}
 
 	for y := 0; y < size; y++ {
@@ -312,5 +395,5 @@ func GeneratePacManDeathFrame(frame int) *ebiten.Image {
 			img.Set(x, y, yellow)
 		}
 	}
-	return img
+	return newImage(img)
 }
@@ -0,0 +1,64 @@
+package game
+
+import (
+	"image"
+	"image/color"
+	"slices"
+	"testing"
+)
+
+// pixelsOf returns the pixels of img in color c, row by row.
+func pixelsOf(img *image.RGBA, c color.RGBA) []image.Point {
+	var pts []image.Point
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if img.RGBAAt(x, y) == c {
+				pts = append(pts, image.Pt(x, y))
+			}
+		}
+	}
+	return pts
+}
+
+func TestGhostEyesLookInDirection(t *testing.T) {
+	body := theme.Palette.Ghosts[Blinky]
+	// The eyes are centered on 4,4 and 8,4; each pupil is two pixels at the
+	// edge the ghost looks towards
+	tests := []struct {
+		dir  Direction
+		want []image.Point
+	}{
+		{DirUp, []image.Point{{4, 2}, {8, 2}, {4, 3}, {8, 3}}},
+		{DirDown, []image.Point{{4, 5}, {8, 5}, {4, 6}, {8, 6}}},
+		{DirLeft, []image.Point{{3, 4}, {7, 4}, {3, 5}, {7, 5}}},
+		{DirRight, []image.Point{{5, 4}, {9, 4}, {5, 5}, {9, 5}}},
+		{DirNone, []image.Point{{5, 4}, {9, 4}, {5, 5}, {9, 5}}}, // looks right
+	}
+	for _, tt := range tests {
+		got := pixelsOf(ghostRGBA(body, tt.dir, 0), theme.Palette.Pupils)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%v: got pupils at %v, want %v", tt.dir, got, tt.want)
+		}
+	}
+}
+
+func TestGhostSkirtAlternates(t *testing.T) {
+	body := theme.Palette.Ghosts[Pinky]
+	// skirt returns the ghost's bottom row
+	skirt := func(frame int) []image.Point {
+		img := ghostRGBA(body, DirLeft, frame)
+		row := img.SubImage(image.Rect(0, GhostSpriteSize-1, GhostSpriteSize, GhostSpriteSize)).(*image.RGBA)
+		return pixelsOf(row, body)
+	}
+	first, second := skirt(0), skirt(1)
+	if len(first) == 0 || len(second) == 0 {
+		t.Fatalf("got skirt rows %v and %v, want bumps in both frames", first, second)
+	}
+	if slices.Equal(first, second) {
+		t.Errorf("both frames have the skirt %v, want it to ripple", first)
+	}
+	if got := skirt(GhostAnimFrames); !slices.Equal(got, first) {
+		t.Errorf("frame %d: got skirt %v, want frame 0's %v", GhostAnimFrames, got, first)
+	}
+}
@@ -0,0 +1,105 @@
+package game
+
+import (
+	"fmt"
+	"image"
+	"sort"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Cell size of a labelled sprite sheet: the sprite with its name underneath.
+const (
+	labelledCellWidth  = 84
+	labelledCellHeight = SpriteSheetCell + fontHeight + 5
+)
+
+// SpriteSheetImage draws every generated sprite into one image.
+//
+// Unlabelled sheets use the layout LoadSpriteSheet reads, on a transparent
+// background, so they can be edited and loaded back with -sprites. Labelled
+// sheets are for reviewing the artwork: each sprite gets a wider cell with its
+// name underneath, and the maze's wall pieces follow in extra rows.
+func SpriteSheetImage(labelled bool) *image.RGBA {
+	slots := spriteSlots(sprites)
+	cols, rows := 0, 0
+	for _, slot := range slots {
+		cols = max(cols, slot.col+1)
+		rows = max(rows, slot.row+1)
+	}
+	if !labelled {
+		sheet := NewSoftCanvas(cols*SpriteSheetCell, rows*SpriteSheetCell)
+		for _, slot := range slots {
+			op := &ebiten.DrawImageOptions{}
+			op.GeoM.Translate(float64(slot.col*SpriteSheetCell), float64(slot.row*SpriteSheetCell))
+			sheet.DrawImage(*slot.img, op)
+		}
+		return sheet.Image
+	}
+
+	walls := wallPieceList(NewMaze())
+	wallRows := (len(walls) + cols - 1) / cols
+	sheet := NewSoftCanvas(cols*labelledCellWidth, (rows+wallRows)*labelledCellHeight)
+	sheet.Fill(theme.Palette.Background)
+	drawCell := func(img *ebiten.Image, name string, col, row int) {
+		x, y := col*labelledCellWidth, row*labelledCellHeight
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Translate(float64(x+2), float64(y+2))
+		sheet.DrawImage(img, op)
+		DrawText(sheet, name, x+2, y+SpriteSheetCell+3, theme.Palette.Text)
+	}
+	for _, slot := range slots {
+		drawCell(*slot.img, slot.name, slot.col, slot.row)
+	}
+	for i, p := range walls {
+		name := fmt.Sprintf("%s %02x", p.style, p.mask)
+		piece := GenerateWallPiece(p, theme.Palette.Wall)
+		drawCell(piece, name, i%cols, rows+i/cols)
+		forgetImage(piece)
+	}
+	return sheet.Image
+}
+
+// wallPieceList returns the distinct wall pieces used by a maze, sorted by
+// style and then neighbour mask.
+func wallPieceList(m *Maze) []wallPiece {
+	seen := make(map[wallPiece]bool)
+	var list []wallPiece
+	for _, row := range mazeWallPieces(m) {
+		for _, p := range row {
+			if p.style != wallNone && !seen[p] {
+				seen[p] = true
+				list = append(list, p)
+			}
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].style != list[j].style {
+			return list[i].style < list[j].style
+		}
+		return list[i].mask < list[j].mask
+	})
+	return list
+}
+
+// String returns the style's name, as used in sprite sheet labels.
+func (s wallStyle) String() string {
+	switch s {
+	case wallSingle:
+		return "single"
+	case wallDouble:
+		return "double"
+	case wallThin:
+		return "thin"
+	default:
+		return "none"
+	}
+}
+
+// ReadImage copies an Ebitengine image into an image.RGBA. Like
+// (*ebiten.Image).ReadPixels, it only works once the game loop is running.
+func ReadImage(img *ebiten.Image) *image.RGBA {
+	out := image.NewRGBA(img.Bounds())
+	img.ReadPixels(out.Pix)
+	return out
+}
@@ -10,16 +10,20 @@ const (
 	StateDeath
 	StateLevelClear
 	StateGameOver
+	StateSettings
+	StateEditor
 )
 
 // validTransitions defines which state transitions are allowed.
 var validTransitions = map[GameState][]GameState{
-	StateTitle:      {StateReady},
-	StateReady:      {StatePlaying},
-	StatePlaying:    {StateDeath, StateLevelClear},
-	StateDeath:      {StatePlaying, StateGameOver},
-	StateLevelClear: {StateReady},
-	StateGameOver:   {StateTitle},
+	StateTitle:      {StateReady, StateSettings, StateEditor},
+	StateSettings:   {StateTitle},
+	StateEditor:     {StateTitle, StateReady},                                  // test play
+	StateReady:      {StatePlaying, StateEditor},                               // Escape ends test play
+	StatePlaying:    {StateDeath, StateLevelClear, StateGameOver, StateEditor}, // game over when a mode's clock runs out
+	StateDeath:      {StatePlaying, StateGameOver, StateEditor},
+	StateLevelClear: {StateReady, StateEditor},
+	StateGameOver:   {StateTitle, StateEditor},
 }
 
 // isValidTransition returns true if the transition from -> to is allowed.
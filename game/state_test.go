@@ -13,8 +13,15 @@ func TestStateTransitions(t *testing.T) {
 		{StateDeath, StatePlaying, "respawn"},
 		{StateDeath, StateGameOver, "no_lives"},
 		{StatePlaying, StateLevelClear, "all_dots_eaten"},
+		{StatePlaying, StateGameOver, "time_up"},
 		{StateLevelClear, StateReady, "next_level"},
 		{StateGameOver, StateTitle, "continue"},
+		{StateTitle, StateSettings, "open_settings"},
+		{StateSettings, StateTitle, "close_settings"},
+		{StateTitle, StateEditor, "open_editor"},
+		{StateEditor, StateReady, "test_play"},
+		{StatePlaying, StateEditor, "stop_test"},
+		{StateGameOver, StateEditor, "test_over"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.event, func(t *testing.T) {
@@ -0,0 +1,415 @@
+package game
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Waveform is the oscillator shape of a synth voice.
+type Waveform int
+
+const (
+	WaveSquare Waveform = iota
+	WaveTriangle
+	WaveSine
+	WaveNoise
+)
+
+var waveformNames = map[string]Waveform{
+	"square":   WaveSquare,
+	"triangle": WaveTriangle,
+	"sine":     WaveSine,
+	"noise":    WaveNoise,
+}
+
+// Envelope is an ADSR amplitude envelope. Times are in seconds and Sustain is
+// a level from 0 to 1. A note holds for its length minus Release, then fades
+// out over Release, so it always ends exactly on its length.
+type Envelope struct {
+	Attack, Decay, Sustain, Release float64
+}
+
+// Note is one note of a voice. Freq is in Hz and 0 is a rest. When To is
+// non-zero the pitch slides linearly from Freq to To over the note.
+type Note struct {
+	Freq, To float64
+	Length   float64 // seconds
+}
+
+// Voice is one monophonic line of a sequence.
+type Voice struct {
+	Wave         Waveform
+	Duty         float64 // high fraction of a square wave's cycle
+	Env          Envelope
+	Volume       float64 // peak amplitude, 0 to 1
+	VibratoRate  float64 // Hz
+	VibratoDepth float64 // Hz
+	Notes        []Note
+}
+
+// Sequence is a sound effect or tune: voices that play together and are mixed.
+type Sequence struct {
+	Voices []*Voice
+}
+
+// newVoice returns a voice with the defaults used before any directive.
+func newVoice() *Voice {
+	return &Voice{Wave: WaveSquare, Duty: 0.5, Env: Envelope{Sustain: 1}, Volume: 0.25}
+}
+
+// ParseSequence reads a sequence in the text format used by the files in sounds/.
+//
+// Each line is a directive or a list of notes; # starts a comment.
+//
+//	tempo 120              quarter notes per minute for note lengths (default 120)
+//	voice                  start another voice, mixed with the ones before it
+//	wave square 0.25       square [duty], triangle, sine or noise (default square 0.5)
+//	envelope A D S R       attack and decay in seconds, sustain level, release in seconds
+//	volume 0.2             peak amplitude from 0 to 1 (default 0.25)
+//	vibrato 6 20           pitch wobble rate and depth, both in Hz
+//
+// A note is PITCH[>PITCH][:LENGTH]. PITCH is a note name with octave (C4,
+// F#5, Bb3), a frequency in Hz (440) or R for a rest; PITCH>PITCH slides from
+// one to the other. LENGTH is a note value (4 is a quarter, 8 an eighth, 4.
+// a dotted quarter) or seconds (0.3s), and carries over to later notes until
+// changed. The default length is a quarter.
+func ParseSequence(r io.Reader) (*Sequence, error) {
+	seq := &Sequence{}
+	v := newVoice()
+	seq.Voices = append(seq.Voices, v)
+	tempo := 120.0
+	length := "4"
+
+	sc := bufio.NewScanner(r)
+	for lineNo := 1; sc.Scan(); lineNo++ {
+		line := sc.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		fail := func(format string, args ...any) error {
+			return fmt.Errorf("line %d: %s", lineNo, fmt.Sprintf(format, args...))
+		}
+		nums := func(lo, hi int) ([]float64, error) {
+			args := fields[1:]
+			if len(args) < lo || len(args) > hi {
+				if lo == hi {
+					return nil, fail("%s takes %d number(s)", fields[0], lo)
+				}
+				return nil, fail("%s takes %d to %d numbers", fields[0], lo, hi)
+			}
+			out := make([]float64, len(args))
+			for i, a := range args {
+				f, err := strconv.ParseFloat(a, 64)
+				if err != nil || f < 0 {
+					return nil, fail("%s: bad number %q", fields[0], a)
+				}
+				out[i] = f
+			}
+			return out, nil
+		}
+
+		switch fields[0] {
+		case "tempo":
+			n, err := nums(1, 1)
+			if err != nil {
+				return nil, err
+			}
+			if n[0] == 0 {
+				return nil, fail("tempo must be positive")
+			}
+			tempo = n[0]
+		case "voice":
+			if len(fields) > 1 {
+				return nil, fail("voice takes no arguments")
+			}
+			v = newVoice()
+			seq.Voices = append(seq.Voices, v)
+		case "wave":
+			if len(fields) < 2 {
+				return nil, fail("wave needs a waveform")
+			}
+			w, ok := waveformNames[fields[1]]
+			if !ok {
+				return nil, fail("unknown waveform %q", fields[1])
+			}
+			v.Wave = w
+			if len(fields) > 2 {
+				if w != WaveSquare || len(fields) > 3 {
+					return nil, fail("only square takes a duty cycle")
+				}
+				d, err := strconv.ParseFloat(fields[2], 64)
+				if err != nil || d <= 0 || d >= 1 {
+					return nil, fail("duty cycle must be between 0 and 1, got %q", fields[2])
+				}
+				v.Duty = d
+			}
+		case "envelope":
+			n, err := nums(4, 4)
+			if err != nil {
+				return nil, err
+			}
+			if n[2] > 1 {
+				return nil, fail("sustain level must be 0 to 1")
+			}
+			v.Env = Envelope{Attack: n[0], Decay: n[1], Sustain: n[2], Release: n[3]}
+		case "volume":
+			n, err := nums(1, 1)
+			if err != nil {
+				return nil, err
+			}
+			if n[0] > 1 {
+				return nil, fail("volume must be 0 to 1")
+			}
+			v.Volume = n[0]
+		case "vibrato":
+			n, err := nums(2, 2)
+			if err != nil {
+				return nil, err
+			}
+			v.VibratoRate, v.VibratoDepth = n[0], n[1]
+		default:
+			for _, tok := range fields {
+				note, err := parseNote(tok, &length, tempo)
+				if err != nil {
+					return nil, fail("%v", err)
+				}
+				v.Notes = append(v.Notes, note)
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return seq, nil
+}
+
+// parseNote parses one note token. length holds the current default length
+// and is updated when the token sets one.
+func parseNote(tok string, length *string, tempo float64) (Note, error) {
+	pitch := tok
+	if i := strings.IndexByte(tok, ':'); i >= 0 {
+		pitch, *length = tok[:i], tok[i+1:]
+	}
+	secs, err := parseLength(*length, tempo)
+	if err != nil {
+		return Note{}, fmt.Errorf("note %q: %v", tok, err)
+	}
+	note := Note{Length: secs}
+	from, to, slide := strings.Cut(pitch, ">")
+	if note.Freq, err = parsePitch(from); err != nil {
+		return Note{}, fmt.Errorf("note %q: %v", tok, err)
+	}
+	if slide {
+		if note.To, err = parsePitch(to); err != nil {
+			return Note{}, fmt.Errorf("note %q: %v", tok, err)
+		}
+		if note.Freq == 0 || note.To == 0 {
+			return Note{}, fmt.Errorf("note %q: cannot slide to or from a rest", tok)
+		}
+	}
+	return note, nil
+}
+
+// parseLength converts a note value ("8", "4.") or duration ("0.3s") to seconds.
+func parseLength(s string, tempo float64) (float64, error) {
+	if secs, ok := strings.CutSuffix(s, "s"); ok {
+		f, err := strconv.ParseFloat(secs, 64)
+		if err != nil || f <= 0 {
+			return 0, fmt.Errorf("bad duration %q", s)
+		}
+		return f, nil
+	}
+	dotted := strings.HasSuffix(s, ".")
+	n, err := strconv.Atoi(strings.TrimSuffix(s, "."))
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("bad length %q", s)
+	}
+	secs := 60 / tempo * 4 / float64(n)
+	if dotted {
+		secs *= 1.5
+	}
+	return secs, nil
+}
+
+// semitones maps note letters to semitones above C.
+var semitones = map[byte]int{'C': 0, 'D': 2, 'E': 4, 'F': 5, 'G': 7, 'A': 9, 'B': 11}
+
+// parsePitch converts a note name (A4, C#5, Bb3), a frequency in Hz or R to Hz.
+func parsePitch(s string) (float64, error) {
+	if s == "R" {
+		return 0, nil
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		if f <= 0 {
+			return 0, fmt.Errorf("frequency must be positive")
+		}
+		return f, nil
+	}
+	if len(s) < 2 {
+		return 0, fmt.Errorf("bad pitch %q", s)
+	}
+	semi, ok := semitones[s[0]]
+	if !ok {
+		return 0, fmt.Errorf("bad pitch %q", s)
+	}
+	rest := s[1:]
+	switch rest[0] {
+	case '#':
+		semi++
+		rest = rest[1:]
+	case 'b':
+		semi--
+		rest = rest[1:]
+	}
+	octave, err := strconv.Atoi(rest)
+	if err != nil {
+		return 0, fmt.Errorf("bad pitch %q", s)
+	}
+	// MIDI note number, with A4 = 69 = 440 Hz
+	midi := (octave+1)*12 + semi
+	return 440 * math.Pow(2, float64(midi-69)/12), nil
+}
+
+// Duration returns the length of the sequence in seconds: its longest voice.
+func (s *Sequence) Duration() float64 {
+	longest := 0.0
+	for _, v := range s.Voices {
+		total := 0.0
+		for _, n := range v.Notes {
+			total += n.Length
+		}
+		longest = max(longest, total)
+	}
+	return longest
+}
+
+// Scaled returns a copy of the sequence with every pitch multiplied by pitch
+// and every note shortened by speed.
+func (s *Sequence) Scaled(pitch, speed float64) *Sequence {
+	out := &Sequence{}
+	for _, v := range s.Voices {
+		cp := *v
+		cp.Notes = make([]Note, len(v.Notes))
+		for i, n := range v.Notes {
+			cp.Notes[i] = Note{Freq: n.Freq * pitch, To: n.To * pitch, Length: n.Length / speed}
+		}
+		out.Voices = append(out.Voices, &cp)
+	}
+	return out
+}
+
+// Render synthesizes the sequence as 16-bit signed little-endian stereo PCM.
+func (s *Sequence) Render(sr int) []byte {
+	mix := make([]float64, int(math.Round(s.Duration()*float64(sr))))
+	for _, v := range s.Voices {
+		v.render(mix, sr)
+	}
+	buf := make([]byte, 0, len(mix)*4)
+	for _, x := range mix {
+		val := uint16(int16(math.Round(max(-1, min(1, x)) * math.MaxInt16)))
+		buf = binary.LittleEndian.AppendUint16(buf, val) // left
+		buf = binary.LittleEndian.AppendUint16(buf, val) // right
+	}
+	return buf
+}
+
+// render adds the voice to mix. The oscillator phase carries across notes so
+// slides and repeated notes join without clicks.
+func (v *Voice) render(mix []float64, sr int) {
+	var (
+		phase float64
+		noise = newNoise()
+		t     float64 // start of the current note in seconds
+	)
+	for _, n := range v.Notes {
+		start := int(math.Round(t * float64(sr)))
+		t += n.Length
+		end := min(int(math.Round(t*float64(sr))), len(mix))
+		if n.Freq == 0 {
+			continue
+		}
+		for i := start; i < end; i++ {
+			pos := float64(i-start) / float64(sr) // seconds into the note
+			freq := n.Freq
+			if n.To != 0 {
+				freq += (n.To - n.Freq) * float64(i-start) / float64(end-start)
+			}
+			if v.VibratoDepth != 0 {
+				freq += v.VibratoDepth * math.Sin(2*math.Pi*v.VibratoRate*(float64(i)/float64(sr)))
+			}
+			phase += freq / float64(sr)
+			if phase >= 1 {
+				phase -= math.Floor(phase)
+				noise.step()
+			}
+			mix[i] += v.Volume * v.Env.level(pos, n.Length) * v.sample(phase, noise)
+		}
+	}
+}
+
+// sample returns the waveform value from -1 to 1 at a phase in [0, 1).
+func (v *Voice) sample(phase float64, noise *noiseGen) float64 {
+	switch v.Wave {
+	case WaveTriangle:
+		return 4*math.Abs(phase-0.5) - 1
+	case WaveSine:
+		return math.Sin(2 * math.Pi * phase)
+	case WaveNoise:
+		return noise.value()
+	default:
+		if phase < v.Duty {
+			return 1
+		}
+		return -1
+	}
+}
+
+// level returns the envelope amplitude at pos seconds into a note of the given length.
+func (e Envelope) level(pos, length float64) float64 {
+	gate := max(length-e.Release, 0)
+	held := func(t float64) float64 {
+		switch {
+		case t < e.Attack:
+			return t / e.Attack
+		case t < e.Attack+e.Decay:
+			return 1 - (1-e.Sustain)*(t-e.Attack)/e.Decay
+		default:
+			return e.Sustain
+		}
+	}
+	if pos < gate || e.Release == 0 {
+		return held(pos)
+	}
+	return held(gate) * max(0, 1-(pos-gate)/e.Release)
+}
+
+// noiseGen is a 15-bit linear feedback shift register, the noise source of
+// classic sound chips. It is seeded the same every time so renders are repeatable.
+type noiseGen struct {
+	reg uint16
+}
+
+func newNoise() *noiseGen {
+	return &noiseGen{reg: 1}
+}
+
+func (n *noiseGen) step() {
+	bit := (n.reg ^ n.reg>>1) & 1
+	n.reg = n.reg>>1 | bit<<14
+}
+
+func (n *noiseGen) value() float64 {
+	if n.reg&1 != 0 {
+		return 1
+	}
+	return -1
+}
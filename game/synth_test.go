@@ -0,0 +1,185 @@
+package game
+
+import (
+	"encoding/binary"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestParsePitch(t *testing.T) {
+	tests := []struct {
+		in   string
+		want float64
+	}{
+		{"A4", 440},
+		{"A5", 880},
+		{"C4", 261.63},
+		{"C#4", 277.18},
+		{"Db4", 277.18},
+		{"Bb3", 233.08},
+		{"1200", 1200},
+		{"R", 0},
+	}
+	for _, tt := range tests {
+		got, err := parsePitch(tt.in)
+		if err != nil {
+			t.Errorf("parsePitch(%q): %v", tt.in, err)
+			continue
+		}
+		if math.Abs(got-tt.want) > 0.01 {
+			t.Errorf("parsePitch(%q): got %.2f, want %.2f", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseSequence(t *testing.T) {
+	seq, err := ParseSequence(strings.NewReader(`
+# two voices
+tempo 60
+wave square 0.125
+envelope 0.01 0.1 0.5 0.05
+C4:4 D4 E4:8. R
+voice
+wave noise
+800>200:0.3s
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(seq.Voices) != 2 {
+		t.Fatalf("voices: got %d, want 2", len(seq.Voices))
+	}
+	v := seq.Voices[0]
+	if v.Wave != WaveSquare || v.Duty != 0.125 || v.Env.Sustain != 0.5 {
+		t.Errorf("first voice settings: got %+v", v)
+	}
+	// At 60 bpm a quarter is 1 s; the length carries over to D4
+	wantLengths := []float64{1, 1, 0.75, 0.75}
+	for i, n := range v.Notes {
+		if math.Abs(n.Length-wantLengths[i]) > 1e-9 {
+			t.Errorf("note %d length: got %f, want %f", i, n.Length, wantLengths[i])
+		}
+	}
+	if v.Notes[3].Freq != 0 {
+		t.Error("R should be a rest")
+	}
+	slide := seq.Voices[1].Notes[0]
+	if slide.Freq != 800 || slide.To != 200 || slide.Length != 0.3 {
+		t.Errorf("slide: got %+v", slide)
+	}
+	if got := seq.Duration(); math.Abs(got-3.5) > 1e-9 {
+		t.Errorf("duration: got %f, want 3.5", got)
+	}
+}
+
+func TestParseSequenceErrors(t *testing.T) {
+	tests := []string{
+		"wave sawtooth",
+		"wave triangle 0.5",
+		"wave square 1.5",
+		"envelope 0 0 2 0",
+		"volume loud",
+		"tempo 0",
+		"H4",
+		"C4:0",
+		"R>C4",
+	}
+	for _, src := range tests {
+		_, err := ParseSequence(strings.NewReader("volume 0.2\n" + src))
+		if err == nil {
+			t.Errorf("%q: expected an error", src)
+		} else if !strings.HasPrefix(err.Error(), "line 2:") {
+			t.Errorf("%q: error should name line 2, got %v", src, err)
+		}
+	}
+}
+
+// renderSamples renders a sequence and returns its left channel as floats.
+func renderSamples(t *testing.T, src string, sr int) []float64 {
+	t.Helper()
+	seq, err := ParseSequence(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pcm := seq.Render(sr)
+	out := make([]float64, len(pcm)/4)
+	for i := range out {
+		out[i] = float64(int16(binary.LittleEndian.Uint16(pcm[i*4:]))) / math.MaxInt16
+	}
+	return out
+}
+
+func TestRenderSquareDuty(t *testing.T) {
+	// 10 Hz at 1000 samples per second is 100 samples per cycle
+	s := renderSamples(t, "volume 1\nwave square 0.3\n10:1s", 1000)
+	if len(s) != 1000 {
+		t.Fatalf("samples: got %d, want 1000", len(s))
+	}
+	high := 0
+	for _, x := range s {
+		if x > 0 {
+			high++
+		}
+	}
+	if high < 290 || high > 310 {
+		t.Errorf("square with duty 0.3 should be high 30%% of the time, got %d/1000", high)
+	}
+}
+
+func TestRenderEnvelope(t *testing.T) {
+	// Sine at full sustain with a 0.5 s attack and 0.25 s release
+	s := renderSamples(t, "volume 1\nwave sine\nenvelope 0.5 0 1 0.25\n50:2s", 8000)
+	peak := func(from, to float64) float64 {
+		p := 0.0
+		for _, x := range s[int(from*8000):int(to*8000)] {
+			p = max(p, math.Abs(x))
+		}
+		return p
+	}
+	if p := peak(0, 0.1); p > 0.25 {
+		t.Errorf("early attack should be quiet, peak %f", p)
+	}
+	if p := peak(1, 1.5); p < 0.99 {
+		t.Errorf("sustain should be at full volume, peak %f", p)
+	}
+	if p := peak(1.95, 2); p > 0.25 {
+		t.Errorf("end of release should be quiet, peak %f", p)
+	}
+}
+
+func TestNoiseIsRepeatable(t *testing.T) {
+	src := "volume 1\nwave noise\n2000:0.1s"
+	a := renderSamples(t, src, 8000)
+	b := renderSamples(t, src, 8000)
+	seen := map[float64]bool{}
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("noise differs between renders at sample %d", i)
+		}
+		seen[a[i]] = true
+	}
+	if !seen[1] || !seen[-1] {
+		t.Error("noise should produce both high and low samples")
+	}
+}
+
+func TestSoundBank(t *testing.T) {
+	for _, name := range []string{
+		"chomp1", "chomp2", "powerup", "ghosteaten", "death",
+		"levelclear", "intro", "siren", "frightened", "eyes",
+	} {
+		seq, ok := soundBank[name]
+		if !ok {
+			t.Errorf("sound %q missing from sounds/", name)
+			continue
+		}
+		if seq.Duration() <= 0 {
+			t.Errorf("sound %q is empty", name)
+		}
+	}
+	// The intro plays during the 2 second READY! pause
+	if d := soundBank["intro"].Duration(); d >= 2 {
+		t.Errorf("intro lasts %.2f s, should be under 2", d)
+	}
+}
@@ -0,0 +1,327 @@
+package game
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/png" // register the PNG decoder for sprite sheets
+	"os"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Palette holds every color used to generate sprites and draw the screen.
+type Palette struct {
+	Background     color.RGBA
+	Wall           color.RGBA
+	Dot            color.RGBA
+	GhostDoor      color.RGBA
+	PacMan         color.RGBA
+	Ghosts         [4]color.RGBA // one per ghost ID (Blinky, Pinky, Inky, Clyde)
+	Frightened     color.RGBA    // frightened ghost body
+	FrightenedFace color.RGBA    // frightened ghost eyes and mouth
+	Flash          color.RGBA    // frightened ghost body while flashing
+	FlashFace      color.RGBA    // frightened ghost eyes and mouth while flashing
+	Eyes           color.RGBA    // whites of the eyes
+	Pupils         color.RGBA
+	Text           color.RGBA
+	Highlight      color.RGBA // "READY!" and the selected settings row
+}
+
+// Theme is a named palette plus an optional PNG sprite sheet that overrides
+// the generated sprites. See LoadSpriteSheet for the sheet layout.
+type Theme struct {
+	ID          string // used by the -theme flag
+	Name        string // shown on the settings screen
+	Palette     Palette
+	SpriteSheet string // path to a PNG sprite sheet, empty for generated sprites only
+}
+
+// Themes lists the built-in themes in the order the settings screen cycles through them.
+var Themes = []Theme{
+	{
+		ID:   "arcade",
+		Name: "ARCADE",
+		Palette: Palette{
+			Background: color.RGBA{R: 0x00, G: 0x00, B: 0x00, A: 0xFF},
+			Wall:       color.RGBA{R: 0x21, G: 0x21, B: 0xDE, A: 0xFF},
+			Dot:        color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF},
+			GhostDoor:  color.RGBA{R: 0xFF, G: 0xB8, B: 0xFF, A: 0xFF},
+			PacMan:     color.RGBA{R: 0xFF, G: 0xFF, B: 0x00, A: 0xFF},
+			Ghosts: [4]color.RGBA{
+				{R: 0xFF, G: 0x00, B: 0x00, A: 0xFF}, // Blinky - red
+				{R: 0xFF, G: 0xB8, B: 0xFF, A: 0xFF}, // Pinky - pink
+				{R: 0x00, G: 0xFF, B: 0xFF, A: 0xFF}, // Inky - cyan
+				{R: 0xFF, G: 0xB8, B: 0x52, A: 0xFF}, // Clyde - orange
+			},
+			Frightened:     color.RGBA{R: 0x21, G: 0x21, B: 0xFF, A: 0xFF},
+			FrightenedFace: color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF},
+			Flash:          color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF},
+			FlashFace:      color.RGBA{R: 0xFF, G: 0x00, B: 0x00, A: 0xFF},
+			Eyes:           color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF},
+			Pupils:         color.RGBA{R: 0x21, G: 0x21, B: 0xFF, A: 0xFF},
+			Text:           color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF},
+			Highlight:      color.RGBA{R: 0xFF, G: 0xFF, B: 0x00, A: 0xFF},
+		},
+	},
+	{
+		ID:   "mspacman",
+		Name: "MS PAC-MAN",
+		Palette: Palette{
+			Background: color.RGBA{R: 0x00, G: 0x00, B: 0x00, A: 0xFF},
+			Wall:       color.RGBA{R: 0xFF, G: 0x87, B: 0xC5, A: 0xFF},
+			Dot:        color.RGBA{R: 0xDE, G: 0xDE, B: 0xFF, A: 0xFF},
+			GhostDoor:  color.RGBA{R: 0xFF, G: 0xDE, B: 0xAE, A: 0xFF},
+			PacMan:     color.RGBA{R: 0xFF, G: 0xFF, B: 0x00, A: 0xFF},
+			Ghosts: [4]color.RGBA{
+				{R: 0xFF, G: 0x00, B: 0x00, A: 0xFF}, // Blinky - red
+				{R: 0xFF, G: 0xB8, B: 0xDE, A: 0xFF}, // Pinky - pink
+				{R: 0x00, G: 0xDE, B: 0xDE, A: 0xFF}, // Inky - cyan
+				{R: 0xFF, G: 0xB8, B: 0x47, A: 0xFF}, // Sue - orange
+			},
+			Frightened:     color.RGBA{R: 0x21, G: 0x21, B: 0xDE, A: 0xFF},
+			FrightenedFace: color.RGBA{R: 0xFF, G: 0xB8, B: 0xAE, A: 0xFF},
+			Flash:          color.RGBA{R: 0xDE, G: 0xDE, B: 0xFF, A: 0xFF},
+			FlashFace:      color.RGBA{R: 0xFF, G: 0x00, B: 0x00, A: 0xFF},
+			Eyes:           color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF},
+			Pupils:         color.RGBA{R: 0x21, G: 0x21, B: 0xDE, A: 0xFF},
+			Text:           color.RGBA{R: 0xFF, G: 0xB8, B: 0xDE, A: 0xFF},
+			Highlight:      color.RGBA{R: 0xFF, G: 0xFF, B: 0x00, A: 0xFF},
+		},
+	},
+	{
+		ID:   "highcontrast",
+		Name: "HIGH CONTRAST",
+		Palette: Palette{
+			Background: color.RGBA{R: 0x00, G: 0x00, B: 0x00, A: 0xFF},
+			Wall:       color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF},
+			Dot:        color.RGBA{R: 0xFF, G: 0xFF, B: 0x00, A: 0xFF},
+			GhostDoor:  color.RGBA{R: 0xFF, G: 0x00, B: 0xFF, A: 0xFF},
+			PacMan:     color.RGBA{R: 0xFF, G: 0xFF, B: 0x00, A: 0xFF},
+			Ghosts: [4]color.RGBA{
+				{R: 0xFF, G: 0x20, B: 0x20, A: 0xFF}, // Blinky - red
+				{R: 0xFF, G: 0x40, B: 0xFF, A: 0xFF}, // Pinky - magenta
+				{R: 0x20, G: 0xFF, B: 0xFF, A: 0xFF}, // Inky - cyan
+				{R: 0xFF, G: 0x90, B: 0x00, A: 0xFF}, // Clyde - orange
+			},
+			Frightened:     color.RGBA{R: 0x30, G: 0x30, B: 0xFF, A: 0xFF},
+			FrightenedFace: color.RGBA{R: 0xFF, G: 0xFF, B: 0x00, A: 0xFF},
+			Flash:          color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF},
+			FlashFace:      color.RGBA{R: 0x00, G: 0x00, B: 0x00, A: 0xFF},
+			Eyes:           color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF},
+			Pupils:         color.RGBA{R: 0x00, G: 0x00, B: 0x00, A: 0xFF},
+			Text:           color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF},
+			Highlight:      color.RGBA{R: 0xFF, G: 0xFF, B: 0x00, A: 0xFF},
+		},
+	},
+	{
+		// Okabe-Ito colors, distinguishable with red-green and blue-yellow color blindness
+		ID:   "colorsafe",
+		Name: "COLOR SAFE",
+		Palette: Palette{
+			Background: color.RGBA{R: 0x00, G: 0x00, B: 0x00, A: 0xFF},
+			Wall:       color.RGBA{R: 0x56, G: 0xB4, B: 0xE9, A: 0xFF},
+			Dot:        color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF},
+			GhostDoor:  color.RGBA{R: 0xCC, G: 0x79, B: 0xA7, A: 0xFF},
+			PacMan:     color.RGBA{R: 0xF0, G: 0xE4, B: 0x42, A: 0xFF},
+			Ghosts: [4]color.RGBA{
+				{R: 0xD5, G: 0x5E, B: 0x00, A: 0xFF}, // Blinky - vermillion
+				{R: 0xCC, G: 0x79, B: 0xA7, A: 0xFF}, // Pinky - reddish purple
+				{R: 0x00, G: 0x9E, B: 0x73, A: 0xFF}, // Inky - bluish green
+				{R: 0xE6, G: 0x9F, B: 0x00, A: 0xFF}, // Clyde - orange
+			},
+			Frightened:     color.RGBA{R: 0x00, G: 0x72, B: 0xB2, A: 0xFF},
+			FrightenedFace: color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF},
+			Flash:          color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF},
+			FlashFace:      color.RGBA{R: 0x00, G: 0x00, B: 0x00, A: 0xFF},
+			Eyes:           color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF},
+			Pupils:         color.RGBA{R: 0x00, G: 0x00, B: 0x00, A: 0xFF},
+			Text:           color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF},
+			Highlight:      color.RGBA{R: 0xF0, G: 0xE4, B: 0x42, A: 0xFF},
+		},
+	},
+	{
+		// Paul Tol's "bright" scheme, an alternative color-blind-safe set
+		ID:   "colorsafe2",
+		Name: "COLOR SAFE 2",
+		Palette: Palette{
+			Background: color.RGBA{R: 0x00, G: 0x00, B: 0x00, A: 0xFF},
+			Wall:       color.RGBA{R: 0xBB, G: 0xBB, B: 0xBB, A: 0xFF},
+			Dot:        color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF},
+			GhostDoor:  color.RGBA{R: 0xAA, G: 0x33, B: 0x77, A: 0xFF},
+			PacMan:     color.RGBA{R: 0xCC, G: 0xBB, B: 0x44, A: 0xFF},
+			Ghosts: [4]color.RGBA{
+				{R: 0xEE, G: 0x66, B: 0x77, A: 0xFF}, // Blinky - red
+				{R: 0xAA, G: 0x33, B: 0x77, A: 0xFF}, // Pinky - purple
+				{R: 0x66, G: 0xCC, B: 0xEE, A: 0xFF}, // Inky - cyan
+				{R: 0x22, G: 0x88, B: 0x33, A: 0xFF}, // Clyde - green
+			},
+			Frightened:     color.RGBA{R: 0x44, G: 0x77, B: 0xAA, A: 0xFF},
+			FrightenedFace: color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF},
+			Flash:          color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF},
+			FlashFace:      color.RGBA{R: 0x00, G: 0x00, B: 0x00, A: 0xFF},
+			Eyes:           color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF},
+			Pupils:         color.RGBA{R: 0x00, G: 0x00, B: 0x00, A: 0xFF},
+			Text:           color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF},
+			Highlight:      color.RGBA{R: 0xCC, G: 0xBB, B: 0x44, A: 0xFF},
+		},
+	},
+	{
+		ID:   "monochrome",
+		Name: "MONOCHROME",
+		Palette: Palette{
+			Background: color.RGBA{R: 0x00, G: 0x00, B: 0x00, A: 0xFF},
+			Wall:       color.RGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xFF},
+			Dot:        color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF},
+			GhostDoor:  color.RGBA{R: 0xC0, G: 0xC0, B: 0xC0, A: 0xFF},
+			PacMan:     color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF},
+			Ghosts: [4]color.RGBA{
+				{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF}, // Blinky
+				{R: 0xD0, G: 0xD0, B: 0xD0, A: 0xFF}, // Pinky
+				{R: 0xA8, G: 0xA8, B: 0xA8, A: 0xFF}, // Inky
+				{R: 0x80, G: 0x80, B: 0x80, A: 0xFF}, // Clyde
+			},
+			Frightened:     color.RGBA{R: 0x50, G: 0x50, B: 0x50, A: 0xFF},
+			FrightenedFace: color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF},
+			Flash:          color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF},
+			FlashFace:      color.RGBA{R: 0x00, G: 0x00, B: 0x00, A: 0xFF},
+			Eyes:           color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF},
+			Pupils:         color.RGBA{R: 0x00, G: 0x00, B: 0x00, A: 0xFF},
+			Text:           color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF},
+			Highlight:      color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF},
+		},
+	},
+}
+
+// theme is the active theme, used when generating sprites and drawing.
+var theme = Themes[0]
+
+// ThemeByID returns the built-in theme with the given ID.
+func ThemeByID(id string) (Theme, error) {
+	for _, t := range Themes {
+		if t.ID == id {
+			return t, nil
+		}
+	}
+	ids := make([]string, len(Themes))
+	for i, t := range Themes {
+		ids[i] = t.ID
+	}
+	return Theme{}, fmt.Errorf("unknown theme %q (available: %s)", id, strings.Join(ids, ", "))
+}
+
+// SetTheme makes t the active theme, regenerating sprites, the maze background
+// and the minimap.
+func (g *Game) SetTheme(t Theme) error {
+	theme = t
+	g.settings.Theme = t.ID
+	InitSprites()
+	if t.SpriteSheet != "" {
+		if err := LoadSpriteSheet(t.SpriteSheet); err != nil {
+			return err
+		}
+	}
+	g.renderMaze(g.maze)
+	return nil
+}
+
+// SpriteSheetCell is the size in pixels of one cell in a sprite sheet.
+const SpriteSheetCell = 16
+
+// spriteSlot is one sprite in the sprite sheet layout.
+type spriteSlot struct {
+	name     string
+	img      **ebiten.Image
+	col, row int
+	size     int
+}
+
+// spriteSlots returns the sprite sheet layout for s. Each sprite sits in the
+// top-left corner of its own SpriteSheetCell-sized cell:
+//
+//	row 0:   dot, power pellet, ghost door, empty
+//	row 1:   Pac-Man frames 0-2
+//	row 2:   Pac-Man death frames 0-10
+//	row 3-6: Blinky, Pinky, Inky, Clyde; two skirt frames for each Direction (none, up, down, left, right)
+//	row 7:   frightened frames 0-1, flashing frames 0-1, eyes for each Direction,
+//	         letter overlays for Blinky, Pinky, Inky, Clyde
+//	row 8:   fruit: cherries, strawberry, orange, apple, melon, galaxian, bell, key
+func spriteSlots(s *Sprites) []spriteSlot {
+	slots := []spriteSlot{
+		{"dot", &s.Dot, 0, 0, TileSize},
+		{"power pellet", &s.PowerPellet, 1, 0, TileSize},
+		{"ghost door", &s.GhostDoor, 2, 0, TileSize},
+		{"empty", &s.Empty, 3, 0, TileSize},
+	}
+	for i := range s.PacManFrames {
+		slots = append(slots, spriteSlot{fmt.Sprintf("pacman %d", i), &s.PacManFrames[i], i, 1, PacManSpriteSize})
+	}
+	for i := range s.PacManDeath {
+		slots = append(slots, spriteSlot{fmt.Sprintf("death %d", i), &s.PacManDeath[i], i, 2, PacManSpriteSize})
+	}
+	ghostNames := [4]string{"blinky", "pinky", "inky", "clyde"}
+	for id := range s.GhostSprites {
+		for dir := range s.GhostSprites[id] {
+			for f := range s.GhostSprites[id][dir] {
+				name := fmt.Sprintf("%s %s %d", ghostNames[id], Direction(dir), f)
+				slots = append(slots, spriteSlot{name, &s.GhostSprites[id][dir][f], dir*GhostAnimFrames + f, 3 + id, GhostSpriteSize})
+			}
+		}
+	}
+	for f := range s.GhostFrightened {
+		slots = append(slots, spriteSlot{fmt.Sprintf("frightened %d", f), &s.GhostFrightened[f], f, 7, GhostSpriteSize})
+		slots = append(slots, spriteSlot{fmt.Sprintf("flash %d", f), &s.GhostFrightenedFlash[f], GhostAnimFrames + f, 7, GhostSpriteSize})
+	}
+	for dir := range s.GhostEyes {
+		slots = append(slots, spriteSlot{fmt.Sprintf("eyes %s", Direction(dir)), &s.GhostEyes[dir], 2*GhostAnimFrames + dir, 7, GhostSpriteSize})
+	}
+	for id := range s.GhostLetters {
+		slots = append(slots, spriteSlot{fmt.Sprintf("letter %s", ghostNames[id]), &s.GhostLetters[id], 2*GhostAnimFrames + len(s.GhostEyes) + id, 7, GhostSpriteSize})
+	}
+	for i := range s.Fruits {
+		slots = append(slots, spriteSlot{Fruit(i + 1).String(), &s.Fruits[i], i, 8, FruitSpriteSize})
+	}
+	return slots
+}
+
+// LoadSpriteSheet overrides the generated sprites with those found in a PNG
+// sprite sheet laid out as described by spriteSlots. Fully transparent cells
+// keep the generated sprite, so a sheet only needs the sprites it replaces.
+func LoadSpriteSheet(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("sprite sheet: %w", err)
+	}
+	defer f.Close()
+	sheet, _, err := image.Decode(f)
+	if err != nil {
+		return fmt.Errorf("sprite sheet %s: %w", path, err)
+	}
+
+	for _, slot := range spriteSlots(sprites) {
+		x, y := slot.col*SpriteSheetCell, slot.row*SpriteSheetCell
+		r := image.Rect(x, y, x+slot.size, y+slot.size).Add(sheet.Bounds().Min)
+		if !r.In(sheet.Bounds()) || isTransparent(sheet, r) {
+			continue
+		}
+		img := newRGBA(slot.size, slot.size)
+		draw.Draw(img, img.Bounds(), sheet, r.Min, draw.Src)
+		forgetImage(*slot.img)
+		*slot.img = newImage(img)
+	}
+	return nil
+}
+
+// isTransparent returns true if every pixel of img within r has zero alpha.
+func isTransparent(img image.Image, r image.Rectangle) bool {
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if _, _, _, a := img.At(x, y).RGBA(); a != 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
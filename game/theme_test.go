@@ -0,0 +1,71 @@
+package game
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestThemeByID(t *testing.T) {
+	for _, want := range Themes {
+		got, err := ThemeByID(want.ID)
+		if err != nil {
+			t.Fatalf("ThemeByID(%q): %v", want.ID, err)
+		}
+		if got.Name != want.Name {
+			t.Errorf("ThemeByID(%q) returned %q", want.ID, got.Name)
+		}
+	}
+	if _, err := ThemeByID("nope"); err == nil {
+		t.Error("unknown theme should return an error")
+	}
+}
+
+func TestSpriteSlotsDoNotOverlap(t *testing.T) {
+	seen := make(map[[2]int]string)
+	for _, slot := range spriteSlots(&Sprites{}) {
+		cell := [2]int{slot.col, slot.row}
+		if other, ok := seen[cell]; ok {
+			t.Errorf("%s and %s share cell %v", slot.name, other, cell)
+		}
+		seen[cell] = slot.name
+		if slot.size > SpriteSheetCell {
+			t.Errorf("%s is larger than a sheet cell", slot.name)
+		}
+	}
+}
+
+func TestLoadSpriteSheetOverridesFilledCells(t *testing.T) {
+	InitSprites()
+	before := *sprites
+
+	// Sheet with only the power pellet cell (column 1, row 0) filled
+	sheet := image.NewRGBA(image.Rect(0, 0, 16*SpriteSheetCell, 8*SpriteSheetCell))
+	for y := 0; y < TileSize; y++ {
+		for x := SpriteSheetCell; x < SpriteSheetCell+TileSize; x++ {
+			sheet.Set(x, y, color.RGBA{R: 0xFF, A: 0xFF})
+		}
+	}
+	path := filepath.Join(t.TempDir(), "sheet.png")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := png.Encode(f, sheet); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	if err := LoadSpriteSheet(path); err != nil {
+		t.Fatalf("LoadSpriteSheet: %v", err)
+	}
+	if sprites.PowerPellet == before.PowerPellet {
+		t.Error("filled cell should override the power pellet sprite")
+	}
+	if sprites.Dot != before.Dot {
+		t.Error("transparent cell should keep the generated dot sprite")
+	}
+}
@@ -0,0 +1,371 @@
+package game
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// wallStyle selects how a wall tile's outline is drawn.
+type wallStyle int
+
+const (
+	wallNone   wallStyle = iota // not drawn (open tile or unreachable space outside the maze)
+	wallSingle                  // inner wall blocks: one rounded line
+	wallDouble                  // outer border: two parallel rounded lines
+	wallThin                    // ghost house: one thin line through the tile center
+)
+
+// Neighbour bits for a wall tile's 8-neighbour mask, clockwise from north.
+// For wallSingle and wallDouble a set bit means the neighbour is open space.
+// For wallThin a set bit means the line connects to that (orthogonal) neighbour.
+const (
+	nbN uint8 = 1 << iota
+	nbNE
+	nbE
+	nbSE
+	nbS
+	nbSW
+	nbW
+	nbNW
+)
+
+// wallLineOffsets is the distance in pixels from the open side of a tile to each
+// outline drawn for a style. wallCornerReach sets how far into a tile convex
+// corners start to round, so the outermost line has a radius of 1 pixel.
+var (
+	wallLineOffsets = map[wallStyle][]float64{
+		wallSingle: {3},
+		wallDouble: {3, 6},
+	}
+	wallCornerReach = map[wallStyle]float64{
+		wallSingle: 6,
+		wallDouble: 7,
+	}
+)
+
+// wallPiece identifies one generated wall piece: a style plus neighbour mask.
+type wallPiece struct {
+	style wallStyle
+	mask  uint8
+}
+
+// mazeWallPieces autotiles the maze: for every tile it returns the wall piece to draw.
+// Open space is every non-wall tile reachable from Pac-Man's spawn; walls and
+// unreachable pockets (such as the blocks beside the tunnels) are solid. Solid
+// regions touching the board edge get the double border, walls next to the ghost
+// house get thin lines, and everything else gets a single outline.
+func mazeWallPieces(m *Maze) [][]wallPiece {
+	open := mazeReachable(m)
+	isOpen := func(x, y int) bool {
+		return open[wrapIndex(y, m.Height)][wrapIndex(x, m.Width)]
+	}
+	border := mazeBorderWalls(m, open)
+
+	pieces := make([][]wallPiece, m.Height)
+	for y := 0; y < m.Height; y++ {
+		pieces[y] = make([]wallPiece, m.Width)
+		for x := 0; x < m.Width; x++ {
+			if open[y][x] || m.TileAt(x, y) != TileWall {
+				continue
+			}
+			switch {
+			case isGhostHouseWall(m, x, y):
+				pieces[y][x] = wallPiece{wallThin, ghostHouseWallMask(m, x, y)}
+			case border[y][x]:
+				pieces[y][x] = wallPiece{wallDouble, wallNeighbourMask(isOpen, x, y)}
+			default:
+				pieces[y][x] = wallPiece{wallSingle, wallNeighbourMask(isOpen, x, y)}
+			}
+		}
+	}
+	return pieces
+}
+
+// neighbourOffsets lists the 8 neighbours in the same order as the nb* bits.
+var neighbourOffsets = [8][2]int{
+	{0, -1}, {1, -1}, {1, 0}, {1, 1}, {0, 1}, {-1, 1}, {-1, 0}, {-1, -1},
+}
+
+// wallNeighbourMask returns the 8-neighbour mask of open tiles around (x, y).
+func wallNeighbourMask(isOpen func(x, y int) bool, x, y int) uint8 {
+	var mask uint8
+	for i, off := range neighbourOffsets {
+		if isOpen(x+off[0], y+off[1]) {
+			mask |= 1 << i
+		}
+	}
+	return mask
+}
+
+// isGhostHouseWall returns true if any of the wall's 8 neighbours is ghost house or door.
+func isGhostHouseWall(m *Maze, x, y int) bool {
+	for _, off := range neighbourOffsets {
+		t := m.TileAt(x+off[0], y+off[1])
+		if t == TileGhostHouse || t == TileGhostDoor {
+			return true
+		}
+	}
+	return false
+}
+
+// ghostHouseWallMask returns which orthogonal neighbours a thin ghost house line connects to.
+func ghostHouseWallMask(m *Maze, x, y int) uint8 {
+	var mask uint8
+	for i, off := range neighbourOffsets {
+		if off[0] != 0 && off[1] != 0 {
+			continue
+		}
+		nx, ny := x+off[0], y+off[1]
+		t := m.TileAt(nx, ny)
+		if t == TileGhostDoor || (t == TileWall && isGhostHouseWall(m, nx, ny)) {
+			mask |= 1 << i
+		}
+	}
+	return mask
+}
+
+// mazeReachable flood-fills non-wall tiles from Pac-Man's spawn, through
+// tunnels and portals.
+func mazeReachable(m *Maze) [][]bool {
+	open := make([][]bool, m.Height)
+	for y := range open {
+		open[y] = make([]bool, m.Width)
+	}
+	type point struct{ x, y int }
+	spawn := m.Spawns.PacMan
+	queue := []point{{spawn.X, spawn.Y}}
+	open[spawn.Y][spawn.X] = true
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, d := range []Direction{DirUp, DirLeft, DirDown, DirRight} {
+			nx, ny := m.Step(cur.x, cur.y, d)
+			if m.TileAt(nx, ny) == TileWall || open[ny][nx] {
+				continue
+			}
+			open[ny][nx] = true
+			queue = append(queue, point{nx, ny})
+		}
+	}
+	return open
+}
+
+// mazeBorderWalls marks solid tiles connected to the edge of the board.
+func mazeBorderWalls(m *Maze, open [][]bool) [][]bool {
+	border := make([][]bool, m.Height)
+	for y := range border {
+		border[y] = make([]bool, m.Width)
+	}
+	type point struct{ x, y int }
+	var queue []point
+	mark := func(x, y int) {
+		if x < 0 || x >= m.Width || y < 0 || y >= m.Height || open[y][x] || border[y][x] {
+			return
+		}
+		border[y][x] = true
+		queue = append(queue, point{x, y})
+	}
+	for x := 0; x < m.Width; x++ {
+		mark(x, 0)
+		mark(x, m.Height-1)
+	}
+	for y := 0; y < m.Height; y++ {
+		mark(0, y)
+		mark(m.Width-1, y)
+	}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, d := range []Direction{DirUp, DirLeft, DirDown, DirRight} {
+			mark(nextTile(cur.x, cur.y, d))
+		}
+	}
+	return border
+}
+
+// wallPiecePixels rasterizes an 8x8 wall piece.
+//
+// Each quadrant of the tile is classified by its horizontal, vertical and diagonal
+// neighbour: both sides open is a convex corner (rounded), one side open is a
+// straight edge, only the diagonal open is a concave corner (an arc around the
+// tile corner), and fully enclosed quadrants draw nothing. A pixel is lit when its
+// distance to open space matches one of the style's line offsets, which makes
+// edges, corners and junctions of neighbouring pieces line up.
+func wallPiecePixels(p wallPiece) [TileSize][TileSize]bool {
+	var px [TileSize][TileSize]bool
+	if p.style == wallThin {
+		const c = TileSize / 2
+		px[c][c] = true
+		for i := 0; i < TileSize; i++ {
+			if (p.mask&nbN != 0 && i < c) || (p.mask&nbS != 0 && i > c) {
+				px[i][c] = true
+			}
+			if (p.mask&nbW != 0 && i < c) || (p.mask&nbE != 0 && i > c) {
+				px[c][i] = true
+			}
+		}
+		return px
+	}
+
+	offsets := wallLineOffsets[p.style]
+	reach := wallCornerReach[p.style]
+	quadrants := []struct {
+		h, v, d uint8
+		left    bool
+		top     bool
+	}{
+		{nbW, nbN, nbNW, true, true},
+		{nbE, nbN, nbNE, false, true},
+		{nbW, nbS, nbSW, true, false},
+		{nbE, nbS, nbSE, false, false},
+	}
+
+	for y := 0; y < TileSize; y++ {
+		for x := 0; x < TileSize; x++ {
+			dist := math.Inf(1)
+			for _, q := range quadrants {
+				u, w := float64(TileSize-1-x), float64(TileSize-1-y)
+				if q.left {
+					u = float64(x)
+				}
+				if q.top {
+					w = float64(y)
+				}
+				hOpen, vOpen := p.mask&q.h != 0, p.mask&q.v != 0
+				var d float64
+				switch {
+				case hOpen && vOpen:
+					d = math.Min(u, w)
+					if d < reach {
+						d = reach - math.Hypot(math.Max(reach-u, 0), math.Max(reach-w, 0))
+					}
+				case hOpen:
+					d = u
+				case vOpen:
+					d = w
+				case p.mask&q.d != 0:
+					d = math.Hypot(u, w)
+				default:
+					continue
+				}
+				dist = math.Min(dist, d)
+			}
+			for _, o := range offsets {
+				if math.Abs(dist-o) < 0.5 {
+					px[y][x] = true
+				}
+			}
+		}
+	}
+	return px
+}
+
+// GenerateWallPiece returns an 8x8 wall piece image in the given color.
+func GenerateWallPiece(p wallPiece, c color.Color) *ebiten.Image {
+	return newImage(wallPieceRGBA(p, c))
+}
+
+// wallPieceRGBA draws the wall piece for GenerateWallPiece.
+func wallPieceRGBA(p wallPiece, c color.Color) *image.RGBA {
+	return pixelsRGBA(wallPiecePixels(p), c)
+}
+
+// pixelsRGBA draws the lit pixels of a tile in color c.
+func pixelsRGBA(px [TileSize][TileSize]bool, c color.Color) *image.RGBA {
+	img := newRGBA(TileSize, TileSize)
+	for y := 0; y < TileSize; y++ {
+		for x := 0; x < TileSize; x++ {
+			if px[y][x] {
+				img.Set(x, y, c)
+			}
+		}
+	}
+	return img
+}
+
+// oneWayArrow is the arrowhead drawn on a one-way door pointing right.
+var oneWayArrow = [TileSize]string{
+	"........",
+	".##.....",
+	"..##....",
+	"...##...",
+	"...##...",
+	"..##....",
+	".##.....",
+	"........",
+}
+
+// tileMark returns the mark drawn on a portal or one-way door, given its
+// layout character, and the mark's color: a ring in a color for each pair of
+// portals, or an arrowhead pointing the door's way. ok is false for other
+// tiles.
+func tileMark(ch byte) (px [TileSize][TileSize]bool, c color.Color, ok bool) {
+	const last = TileSize - 1
+	switch {
+	case ch >= '0' && ch <= '9':
+		for y := range TileSize {
+			for x := range TileSize {
+				dx, dy := float64(x)-3.5, float64(y)-3.5
+				d := math.Sqrt(dx*dx + dy*dy)
+				px[y][x] = d >= 1.5 && d <= 3.2
+			}
+		}
+		return px, theme.Palette.Ghosts[(ch-'0')%4], true
+	case oneWayChars[ch] != DirNone:
+		for y := range TileSize {
+			for x := range TileSize {
+				lit := false
+				switch oneWayChars[ch] {
+				case DirRight:
+					lit = oneWayArrow[y][x] == '#'
+				case DirLeft:
+					lit = oneWayArrow[y][last-x] == '#'
+				case DirDown:
+					lit = oneWayArrow[x][y] == '#'
+				case DirUp:
+					lit = oneWayArrow[last-x][y] == '#'
+				}
+				px[y][x] = lit
+			}
+		}
+		return px, theme.Palette.GhostDoor, true
+	}
+	return px, nil, false
+}
+
+// RenderMazeBackground pre-renders the maze walls, ghost door, portals and
+// one-way doors into one image the size of the board, so drawMaze only has to
+// draw dots on top of it.
+func RenderMazeBackground(m *Maze) *ebiten.Image {
+	bg := NewSoftCanvas(m.Width*TileSize, m.Height*TileSize)
+	bg.Fill(theme.Palette.Background)
+
+	cache := make(map[wallPiece]*image.RGBA)
+	for y, row := range mazeWallPieces(m) {
+		for x, p := range row {
+			r := image.Rect(x*TileSize, y*TileSize, (x+1)*TileSize, (y+1)*TileSize)
+			if px, c, ok := tileMark(m.layout[y][x]); ok {
+				draw.Draw(bg.Image, r, pixelsRGBA(px, c), image.Point{}, draw.Over)
+				continue
+			}
+			switch {
+			case m.TileAt(x, y) == TileGhostDoor:
+				op := &ebiten.DrawImageOptions{}
+				op.GeoM.Translate(float64(x*TileSize), float64(y*TileSize))
+				bg.DrawImage(sprites.GhostDoor, op)
+			case p.style != wallNone:
+				tile := cache[p]
+				if tile == nil {
+					tile = wallPieceRGBA(p, theme.Palette.Wall)
+					cache[p] = tile
+				}
+				draw.Draw(bg.Image, r, tile, image.Point{}, draw.Over)
+			}
+		}
+	}
+	return newImage(bg.Image)
+}
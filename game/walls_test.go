@@ -0,0 +1,76 @@
+package game
+
+import "testing"
+
+func TestWallStyles(t *testing.T) {
+	m := NewMaze()
+	pieces := mazeWallPieces(m)
+	tests := []struct {
+		name string
+		x, y int
+		want wallStyle
+	}{
+		{"outer border", 5, 0, wallDouble},
+		{"inner block", 3, 2, wallSingle},
+		{"ghost house wall", 10, 13, wallThin},
+		{"corridor", 1, 1, wallNone},
+		{"pocket beside tunnel", 2, 11, wallNone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pieces[tt.y][tt.x].style; got != tt.want {
+				t.Errorf("style at (%d,%d): got %d, want %d", tt.x, tt.y, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWallNeighbourMask(t *testing.T) {
+	m := NewMaze()
+	pieces := mazeWallPieces(m)
+	// Top-left tile of the block at (2..5, 2..4): corridor above, left and diagonally
+	want := nbN | nbNE | nbW | nbSW | nbNW
+	if got := pieces[2][2].mask; got != want {
+		t.Errorf("mask at (2,2): got %08b, want %08b", got, want)
+	}
+}
+
+func TestWallPieceEdgesLineUp(t *testing.T) {
+	// A straight edge open to the north and its convex corner piece should put
+	// their line on the same row where they meet.
+	edge := wallPiecePixels(wallPiece{wallSingle, nbN | nbNE | nbNW})
+	corner := wallPiecePixels(wallPiece{wallSingle, nbN | nbNE | nbE | nbSE | nbNW})
+	for x := 0; x < TileSize; x++ {
+		if !edge[3][x] {
+			t.Errorf("edge piece should have a line at row 3, column %d", x)
+		}
+	}
+	if !corner[3][0] {
+		t.Error("corner piece should continue the edge line at its left side")
+	}
+	if corner[3][TileSize-1] {
+		t.Error("corner piece should round off before its open right side")
+	}
+}
+
+func TestGhostHouseWallConnectsToDoor(t *testing.T) {
+	m := NewMaze()
+	// (12,12) sits between a ghost house wall and the door
+	p := mazeWallPieces(m)[12][12]
+	if p.mask != nbE|nbW {
+		t.Errorf("ghost house wall mask: got %08b, want %08b", p.mask, nbE|nbW)
+	}
+}
+
+func TestWallPieceList(t *testing.T) {
+	list := wallPieceList(NewMaze())
+	if len(list) == 0 {
+		t.Fatal("maze should use some wall pieces")
+	}
+	for i := 1; i < len(list); i++ {
+		a, b := list[i-1], list[i]
+		if a.style > b.style || (a.style == b.style && a.mask >= b.mask) {
+			t.Errorf("pieces %d and %d out of order or repeated: %v, %v", i-1, i, a, b)
+		}
+	}
+}
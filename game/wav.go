@@ -0,0 +1,39 @@
+package game
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// WriteWAV writes 16-bit stereo PCM, as produced by RenderSound, as a WAV file.
+func WriteWAV(w io.Writer, pcm []byte, sampleRate int) error {
+	const (
+		channels      = 2
+		bitsPerSample = 16
+		blockAlign    = channels * bitsPerSample / 8
+	)
+	header := []any{
+		[4]byte{'R', 'I', 'F', 'F'},
+		uint32(36 + len(pcm)), // size of everything after this field
+		[4]byte{'W', 'A', 'V', 'E'},
+
+		[4]byte{'f', 'm', 't', ' '},
+		uint32(16), // fmt chunk size
+		uint16(1),  // PCM
+		uint16(channels),
+		uint32(sampleRate),
+		uint32(sampleRate * blockAlign), // bytes per second
+		uint16(blockAlign),
+		uint16(bitsPerSample),
+
+		[4]byte{'d', 'a', 't', 'a'},
+		uint32(len(pcm)),
+	}
+	for _, field := range header {
+		if err := binary.Write(w, binary.LittleEndian, field); err != nil {
+			return err
+		}
+	}
+	_, err := w.Write(pcm)
+	return err
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"log"
 
 	"github.com/hajimehoshi/ebiten/v2"
@@ -8,9 +9,115 @@ import (
 )
 
 func main() {
-	ebiten.SetWindowSize(game.ScreenWidth*game.Scale, game.ScreenHeight*game.Scale)
+	// Saved settings are the defaults; flags override them for this run, and
+	// only changes made in the game are saved.
+	settingsPath, err := game.DefaultSettingsPath()
+	if err != nil {
+		log.Printf("settings will not be saved: %v", err)
+	}
+	settings := game.DefaultSettings()
+	if settingsPath != "" {
+		if settings, err = game.LoadSettings(settingsPath); err != nil {
+			log.Print(err)
+		}
+	}
+	saved := settings
+
+	themeID := flag.String("theme", settings.Theme, "color theme: arcade, mspacman, highcontrast, colorsafe, colorsafe2, monochrome")
+	spriteSheet := flag.String("sprites", "", "PNG sprite sheet overriding the generated sprites")
+	flag.StringVar(&settings.Language, "lang", settings.Language, "language: en, de, nb (default from LANG)")
+	flag.BoolVar(&settings.GhostLetters, "ghost-letters", settings.GhostLetters, "draw each ghost's initial on its body")
+	flag.BoolVar(&settings.ReducedFlashing, "reduced-flashing", settings.ReducedFlashing, "disable power pellet blink and level-clear wall strobe")
+	flag.BoolVar(&settings.Minimap, "minimap", settings.Minimap, "show the whole maze and the ghosts beside the view")
+	flag.IntVar(&settings.SpeedPercent, "speed", settings.SpeedPercent, "game speed in percent (50-100)")
+	flag.IntVar(&settings.MasterVolume, "volume", settings.MasterVolume, "master volume in percent (0-100)")
+	flag.BoolVar(&settings.Muted, "mute", settings.Muted, "start with sound muted")
+	flag.IntVar(&settings.WindowScale, "scale", settings.WindowScale, "window size as a multiple of 224x288 (1-6)")
+	flag.BoolVar(&settings.Fullscreen, "fullscreen", settings.Fullscreen, "start fullscreen (toggle with Alt+Enter)")
+	flag.BoolVar(&settings.VSync, "vsync", settings.VSync, "wait for the display's vertical sync")
+	flag.BoolVar(&settings.Resizable, "resizable", settings.Resizable, "allow resizing the window; the game is letterboxed")
+	flag.StringVar(&settings.ScreenFilter, "filter", settings.ScreenFilter, "screen filter: none, scanlines, crt")
+	flag.IntVar(&settings.Orientation, "rotate", settings.Orientation, "rotate the display clockwise by 0, 90, 180 or 270 degrees")
+	flag.BoolVar(&settings.Cocktail, "cocktail", settings.Cocktail, "cocktail table: turn the screen for player two")
+	debugOverlay := flag.Bool("debug-overlay", false, "start with the debug overlay shown (toggle with F3)")
+	noSound := flag.Bool("nosound", false, "run without an audio device")
+	captureDir := flag.String("capture-dir", ".", "directory for F12 screenshots, F9 GIF recordings and F8 replays")
+	gifFPS := flag.Int("gif-fps", 25, "frame rate of F9 GIF recordings (1-50)")
+	rulesPath := flag.String("rules", "", "JSON rules file overriding scoring, lives, timings and difficulty")
+	difficulty := flag.String("difficulty", "", "difficulty preset: classic, arcade (default from the rules file, else classic)")
+	maze := flag.String("maze", "", "maze: classic, or generated for a new maze each level (default from the rules file, else classic)")
+	mazeFile := flag.String("maze-file", "", "maze file to play and edit instead of the rules' maze, as saved by the maze editor")
+	mode := flag.String("mode", game.ModeClassic, "mode selected on the title screen: classic, timeattack, endless, survival")
+	flag.Parse()
+
+	lang := settings.Language
+	if lang == "" {
+		lang = game.DetectLanguage()
+	}
+	if err := game.SetLanguage(lang); err != nil {
+		log.Fatal(err)
+	}
+
+	theme, err := game.ThemeByID(*themeID)
+	if err != nil {
+		log.Fatal(err)
+	}
+	theme.SpriteSheet = *spriteSheet
+
+	rules := game.DefaultRules()
+	if *rulesPath != "" {
+		if rules, err = game.LoadRules(*rulesPath); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *difficulty != "" {
+		rules.Difficulty = *difficulty
+	}
+	if *maze != "" {
+		rules.Maze = *maze
+	}
+	if err := rules.Validate(); err != nil {
+		log.Fatal(err)
+	}
+
+	highScoresPath, err := game.DefaultHighScoresPath()
+	if err != nil {
+		log.Printf("high scores will not be saved: %v", err)
+	}
+	highScores := game.HighScores{}
+	if highScoresPath != "" {
+		if highScores, err = game.LoadHighScores(highScoresPath); err != nil {
+			log.Print(err)
+		}
+	}
+
+	g := game.New()
+	g.SetRules(rules)
+	if err := g.SetMode(*mode); err != nil {
+		log.Fatal(err)
+	}
+	g.SetHighScores(highScores)
+	g.SetHighScoresPath(highScoresPath)
+	if !*noSound {
+		g.SetSound(game.NewSoundManager())
+	}
+	g.SetSettings(settings)
+	if err := g.SetTheme(theme); err != nil {
+		log.Fatal(err)
+	}
+	g.SetSettingsPath(settingsPath, saved)
+	g.SetCaptureDir(*captureDir)
+	if *mazeFile != "" {
+		if err := g.SetMazeFile(*mazeFile); err != nil {
+			log.Fatal(err)
+		}
+	}
+	g.SetGIFFPS(*gifFPS)
+	g.SetDebugOverlay(*debugOverlay)
+
+	g.ApplyWindowSettings()
 	ebiten.SetWindowTitle("Go Pac-Man")
-	if err := ebiten.RunGame(game.New()); err != nil {
+	if err := ebiten.RunGame(g); err != nil {
 		log.Fatal(err)
 	}
 }